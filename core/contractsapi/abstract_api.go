@@ -2,6 +2,7 @@ package contractsapi
 
 import (
 	"context"
+	"github.com/golang-sql/civil"
 	"github.com/kwilteam/kwil-db/core/types/transactions"
 	"github.com/kwilteam/kwil-db/core/utils"
 	"github.com/pkg/errors"
@@ -10,17 +11,25 @@ import (
 )
 
 func (s *Stream) AllowReadWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error) {
-	return s.insertMetadata(ctx, types.AllowReadWalletKey, types.NewMetadataValue(wallet.Address()))
+	return s.InsertMetadata(ctx, types.AllowReadWalletKey, types.NewRefMetadataValue(wallet.Address()))
 }
 
 func (s *Stream) DisableReadWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error) {
 	return s.disableMetadataByRef(ctx, types.AllowReadWalletKey, wallet.Address())
 }
 
+func (s *Stream) AllowWriteWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error) {
+	return s.InsertMetadata(ctx, types.AllowWriteWalletKey, types.NewRefMetadataValue(wallet.Address()))
+}
+
+func (s *Stream) DisableWriteWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error) {
+	return s.disableMetadataByRef(ctx, types.AllowWriteWalletKey, wallet.Address())
+}
+
 func (s *Stream) AllowComposeStream(ctx context.Context, locator types.StreamLocator) (transactions.TxHash, error) {
 	streamId := locator.StreamId
 	dbid := utils.GenerateDBID(streamId.String(), locator.DataProvider.Bytes())
-	return s.insertMetadata(ctx, types.AllowComposeStreamKey, types.NewMetadataValue(dbid))
+	return s.InsertMetadata(ctx, types.AllowComposeStreamKey, types.NewRefMetadataValue(dbid))
 }
 
 func (s *Stream) DisableComposeStream(ctx context.Context, locator types.StreamLocator) (transactions.TxHash, error) {
@@ -58,7 +67,7 @@ func (s *Stream) GetComposeVisibility(ctx context.Context) (*util.VisibilityEnum
 }
 
 func (s *Stream) SetComposeVisibility(ctx context.Context, visibility util.VisibilityEnum) (transactions.TxHash, error) {
-	return s.insertMetadata(ctx, types.ComposeVisibilityKey, types.NewMetadataValue(int(visibility)))
+	return s.InsertMetadata(ctx, types.ComposeVisibilityKey, types.NewMetadataValue(int(visibility)))
 }
 
 func (s *Stream) GetReadVisibility(ctx context.Context) (*util.VisibilityEnum, error) {
@@ -86,8 +95,17 @@ func (s *Stream) GetReadVisibility(ctx context.Context) (*util.VisibilityEnum, e
 }
 
 func (s *Stream) GetAllowedReadWallets(ctx context.Context) ([]util.EthereumAddress, error) {
+	return s.getAllowedWallets(ctx, types.AllowReadWalletKey)
+}
+
+func (s *Stream) GetAllowedWriteWallets(ctx context.Context) ([]util.EthereumAddress, error) {
+	return s.getAllowedWallets(ctx, types.AllowWriteWalletKey)
+}
+
+// getAllowedWallets gets the wallets stored as refs under the given key
+func (s *Stream) getAllowedWallets(ctx context.Context, key types.MetadataKey) ([]util.EthereumAddress, error) {
 	results, err := s.getMetadata(ctx, getMetadataParams{
-		Key: types.AllowReadWalletKey,
+		Key: key,
 	})
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -96,7 +114,7 @@ func (s *Stream) GetAllowedReadWallets(ctx context.Context) ([]util.EthereumAddr
 	wallets := make([]util.EthereumAddress, len(results))
 
 	for i, result := range results {
-		value, err := result.GetValueByKey(types.AllowReadWalletKey)
+		value, err := result.GetValueByKey(key)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -135,36 +153,108 @@ func (s *Stream) GetAllowedComposeStreams(ctx context.Context) ([]types.StreamLo
 			return nil, errors.New("invalid value type")
 		}
 
-		loc, err := s._client.GetSchema(ctx, dbid)
+		streams[i], err = s.streamLocatorFromDBID(ctx, dbid)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+	}
 
-		streamId, err := util.NewStreamId(loc.Name)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
+	return streams, nil
+}
 
-		owner, err := util.NewEthereumAddressFromString(loc.Owner.String())
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
+// streamLocatorFromDBID gets the stream id and data provider of a DBID from its schema
+func (s *Stream) streamLocatorFromDBID(ctx context.Context, dbid string) (types.StreamLocator, error) {
+	loc, err := s._client.GetSchema(ctx, dbid)
+	if err != nil {
+		return types.StreamLocator{}, errors.WithStack(err)
+	}
 
-		streams[i] = types.StreamLocator{
-			StreamId:     *streamId,
-			DataProvider: owner,
-		}
+	streamId, err := util.NewStreamId(loc.Name)
+	if err != nil {
+		return types.StreamLocator{}, errors.WithStack(err)
 	}
 
-	return streams, nil
+	owner, err := util.NewEthereumAddressFromString(loc.Owner.String())
+	if err != nil {
+		return types.StreamLocator{}, errors.WithStack(err)
+	}
+
+	return types.StreamLocator{
+		StreamId:     *streamId,
+		DataProvider: owner,
+	}, nil
 }
 
 func (s *Stream) SetReadVisibility(ctx context.Context, visibility util.VisibilityEnum) (transactions.TxHash, error) {
-	return s.insertMetadata(ctx, types.ReadVisibilityKey, types.NewMetadataValue(int(visibility)))
+	return s.InsertMetadata(ctx, types.ReadVisibilityKey, types.NewMetadataValue(int(visibility)))
+}
+
+var ErrorInvalidBaseDate = errors.New("invalid base date")
+
+func (s *Stream) SetDefaultBaseDate(ctx context.Context, baseDate civil.Date) (transactions.TxHash, error) {
+	if !baseDate.IsValid() {
+		return transactions.TxHash{}, errors.Wrap(ErrorInvalidBaseDate, baseDate.String())
+	}
+
+	return s.InsertMetadata(ctx, types.DefaultBaseDateKey, types.NewMetadataValue(baseDate.String()))
+}
+
+func (s *Stream) GetDefaultBaseDate(ctx context.Context) (*civil.Date, error) {
+	values, err := s.getMetadata(ctx, getMetadataParams{
+		Key:        types.DefaultBaseDateKey,
+		OnlyLatest: true,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	baseDate, err := civil.ParseDate(values[0].ValueS)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &baseDate, nil
 }
 
-func (s *Stream) SetDefaultBaseDate(ctx context.Context, baseDate string) (transactions.TxHash, error) {
-	return s.insertMetadata(ctx, types.DefaultBaseDateKey, types.NewMetadataValue(baseDate))
+// ClearDefaultBaseDate disables every default base date row, so earlier dates don't take over
+func (s *Stream) ClearDefaultBaseDate(ctx context.Context) (transactions.TxHash, error) {
+	values, err := s.getMetadata(ctx, getMetadataParams{
+		Key: types.DefaultBaseDateKey,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(values) == 0 {
+		return nil, MetadataValueNotFound
+	}
+
+	rowIds := make([]string, len(values))
+	for i, value := range values {
+		rowIds[i] = value.RowId
+	}
+
+	return s.BatchDisableMetadata(ctx, rowIds)
+}
+
+func (s *Stream) GetDisplayName(ctx context.Context) (string, error) {
+	values, err := s.getMetadata(ctx, getMetadataParams{
+		Key:        types.DisplayNameKey,
+		OnlyLatest: true,
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	return values[0].ValueS, nil
 }
 
 var MetadataValueNotFound = errors.New("metadata value not found")
@@ -184,5 +274,5 @@ func (s *Stream) disableMetadataByRef(ctx context.Context, key types.MetadataKey
 		return nil, MetadataValueNotFound
 	}
 
-	return s.disableMetadata(ctx, metadataList[0].RowId)
+	return s.DisableMetadata(ctx, metadataList[0].RowId)
 }
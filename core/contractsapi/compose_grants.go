@@ -0,0 +1,112 @@
+package contractsapi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+func (c *ComposedStream) GrantComposeToPrivateChildren(ctx context.Context, taxonomies types.Taxonomy) (types.ComposeGrantResults, error) {
+	deployer, err := util.NewEthereumAddressFromBytes(c._deployer)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	parent := types.StreamLocator{
+		StreamId:     c.StreamId,
+		DataProvider: deployer,
+	}
+
+	// the grants are signed by the signer, so the contract accepts them only if it owns the child
+	if len(c._signer) == 0 {
+		return nil, errors.New("signer is required to grant compose")
+	}
+	signer, err := util.NewEthereumAddressFromBytes(c._signer)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var results types.ComposeGrantResults
+	var granted []types.StreamLocator
+	for _, item := range taxonomies.TaxonomyItems {
+		if containsLocator(granted, item.ChildStream) {
+			continue
+		}
+		granted = append(granted, item.ChildStream)
+
+		result, err := c.grantComposeToChild(ctx, parent, signer, item.ChildStream)
+		if err != nil {
+			return results, errors.Wrapf(err, "grant compose on stream %s", item.ChildStream.StreamId.String())
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// grantComposeToChild allows the parent to compose the child, if needed and possible.
+// A nil hash in the result means nothing was sent
+func (c *ComposedStream) grantComposeToChild(
+	ctx context.Context,
+	parent types.StreamLocator,
+	signer util.EthereumAddress,
+	childLocator types.StreamLocator,
+) (types.ComposeGrantResult, error) {
+	result := types.ComposeGrantResult{Child: childLocator}
+
+	child, err := LoadStreamContext(ctx, NewStreamOptions{
+		Client:   c._client,
+		StreamId: childLocator.StreamId,
+		Deployer: childLocator.DataProvider.Bytes(),
+		Signer:   c._signer,
+		Logger:   c._logger,
+	})
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+
+	visibility, err := child.GetComposeVisibility(ctx)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	if visibility == nil || *visibility != util.PrivateVisibility {
+		return result, nil
+	}
+
+	allowed, err := child.CanCompose(ctx, parent)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	if allowed {
+		return result, nil
+	}
+
+	isOwner, err := child.isStreamOwner(ctx, signer)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	if !isOwner {
+		c._logger.Warn("child stream has private compose visibility and is not owned by the signer, ask its owner to allow composing",
+			"streamId", childLocator.StreamId.String(),
+			"dataProvider", childLocator.DataProvider.Address(),
+			"composingStream", c.DBID)
+		result.NotOwned = true
+		return result, nil
+	}
+
+	result.TxHash, err = child.AllowComposeStream(ctx, parent)
+	if err != nil {
+		return result, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+func containsLocator(locators []types.StreamLocator, locator types.StreamLocator) bool {
+	for _, l := range locators {
+		if l.Equals(locator) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package contractsapi_test
+
+import (
+	"context"
+	"testing"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/kwilteam/kwil-db/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestGrantComposeToPrivateChildren checks that private children are granted only if the signer owns
+// them, whoever owns the composed stream.
+func TestGrantComposeToPrivateChildren(t *testing.T) {
+	ctx := context.Background()
+	parentOwner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+	signer := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000456")
+	otherOwner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000789")
+
+	signerChild := types.StreamLocator{StreamId: util.GenerateStreamId("test-grant-signer-child"), DataProvider: signer}
+	otherChild := types.StreamLocator{StreamId: util.GenerateStreamId("test-grant-other-child"), DataProvider: otherOwner}
+	owners := map[string]util.EthereumAddress{
+		utils.GenerateDBID(signerChild.StreamId.String(), signer.Bytes()):    signer,
+		utils.GenerateDBID(otherChild.StreamId.String(), otherOwner.Bytes()): otherOwner,
+	}
+
+	var granted []string
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			switch procedure {
+			case "is_stream_allowed_to_compose":
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value": false}}), nil
+			case "is_stream_owner":
+				owner := owners[dbid]
+				isOwner := owner.Address() == inputs[0]
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"result": isOwner}}), nil
+			}
+			switch inputs[0] {
+			case types.TypeKey.String():
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value_s": "composed"}}), nil
+			case types.ComposeVisibilityKey.String():
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value_i": int(util.PrivateVisibility)}}), nil
+			}
+			return kwilClientType.NewRecordsFromMaps(nil), nil
+		},
+		ExecuteFunc: func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error) {
+			granted = append(granted, dbid)
+			return transactions.TxHash{1}, nil
+		},
+	}
+	taxonomy := types.Taxonomy{TaxonomyItems: []types.TaxonomyItem{
+		{ChildStream: signerChild, Weight: 1},
+		{ChildStream: otherChild, Weight: 1},
+	}}
+
+	stream, err := contractsapi.LoadComposedStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-grant-parent"),
+		Deployer: parentOwner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+	_, err = stream.GrantComposeToPrivateChildren(ctx, taxonomy)
+	assert.Error(t, err, "grants without a signer")
+
+	stream, err = contractsapi.LoadComposedStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-grant-parent"),
+		Deployer: parentOwner.Bytes(),
+		Signer:   signer.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+	results, err := stream.GrantComposeToPrivateChildren(ctx, taxonomy)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.NotNil(t, results[0].TxHash)
+	assert.False(t, results[0].NotOwned)
+	assert.Nil(t, results[1].TxHash)
+	assert.True(t, results[1].NotOwned)
+	assert.Equal(t, []string{utils.GenerateDBID(signerChild.StreamId.String(), signer.Bytes())}, granted)
+}
@@ -18,9 +18,15 @@ type ComposedStream struct {
 var _ types.IComposedStream = (*ComposedStream)(nil)
 
 var (
-	ErrorStreamNotComposed = errors.New("stream is not a composed stream")
+	ErrorStreamNotComposed     = errors.New("stream is not a composed stream")
+	ErrorTaxonomyChildExists   = errors.New("child stream is already part of the taxonomy")
+	ErrorTaxonomyChildNotFound = errors.New("child stream is not part of the taxonomy")
+	ErrorTaxonomyUnchanged     = errors.New("taxonomy edit results in no changes")
+	ErrorTaxonomyEmpty         = errors.New("taxonomy must have at least one child")
+	ErrorTaxonomyStartDate     = errors.New("taxonomy start date must not be before the previous version")
 )
 
+// ComposedStreamFromStream converts the stream. The result shares the cache of the stream, i.e. its type and owner
 func ComposedStreamFromStream(stream Stream) (*ComposedStream, error) {
 	return &ComposedStream{
 		Stream: stream,
@@ -28,7 +34,11 @@ func ComposedStreamFromStream(stream Stream) (*ComposedStream, error) {
 }
 
 func LoadComposedStream(opts NewStreamOptions) (*ComposedStream, error) {
-	stream, err := LoadStream(opts)
+	return LoadComposedStreamContext(context.Background(), opts)
+}
+
+func LoadComposedStreamContext(ctx context.Context, opts NewStreamOptions) (*ComposedStream, error) {
+	stream, err := LoadStreamContext(ctx, opts)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -126,7 +136,23 @@ func (c *ComposedStream) DescribeTaxonomies(ctx context.Context, params types.De
 	}, nil
 }
 
-func (c *ComposedStream) SetTaxonomy(ctx context.Context, taxonomies types.Taxonomy) (transactions.TxHash, error) {
+func (c *ComposedStream) SetTaxonomy(ctx context.Context, taxonomies types.Taxonomy, opts ...types.SetTaxonomyOption) (transactions.TxHash, error) {
+	var options types.SetTaxonomyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// grants are sent first, so they are mined no later than the taxonomy
+	if options.GrantCompose {
+		grantResults, err := c.GrantComposeToPrivateChildren(ctx, taxonomies)
+		if options.GrantResults != nil {
+			*options.GrantResults = grantResults
+		}
+		if err != nil {
+			return transactions.TxHash{}, errors.WithStack(err)
+		}
+	}
+
 	var (
 		dataProviders []string
 		streamIDs     util.StreamIdSlice
@@ -150,3 +176,196 @@ func (c *ComposedStream) SetTaxonomy(ctx context.Context, taxonomies types.Taxon
 	args = append(args, []any{dataProviders, streamIDs.Strings(), weights, startDate})
 	return c.checkedExecute(ctx, "set_taxonomy", args)
 }
+
+// editTaxonomy fetches the latest taxonomy version, applies the edit to its children
+// and publishes the result as the next version
+func (c *ComposedStream) editTaxonomy(
+	ctx context.Context,
+	startDate *civil.Date,
+	edit func(items []types.TaxonomyItem) ([]types.TaxonomyItem, error),
+) (types.TaxonomyEditResult, error) {
+	previous, err := c.DescribeTaxonomies(ctx, types.DescribeTaxonomiesParams{LatestVersion: true})
+	if err != nil {
+		return types.TaxonomyEditResult{}, errors.WithStack(err)
+	}
+
+	// edit a copy, so previous is kept intact for the diff
+	items := make([]types.TaxonomyItem, len(previous.TaxonomyItems))
+	copy(items, previous.TaxonomyItems)
+
+	items, err = edit(items)
+	if err != nil {
+		return types.TaxonomyEditResult{}, errors.WithStack(err)
+	}
+
+	if len(items) == 0 {
+		return types.TaxonomyEditResult{}, ErrorTaxonomyEmpty
+	}
+
+	// the contract picks each weight by the latest start date, so an undated version would lose against
+	// a dated previous one, and an earlier date would only apply before the previous version
+	if startDate == nil {
+		startDate = previous.StartDate
+	}
+	if previous.StartDate != nil && startDate.Before(*previous.StartDate) {
+		return types.TaxonomyEditResult{}, ErrorTaxonomyStartDate
+	}
+
+	next := types.Taxonomy{
+		TaxonomyItems: items,
+		StartDate:     startDate,
+	}
+
+	diff := types.DiffTaxonomies(previous, next)
+	if diff.IsEmpty() {
+		return types.TaxonomyEditResult{}, ErrorTaxonomyUnchanged
+	}
+
+	// with the same start date, the contract has no order between the previous and next weights of a child
+	if len(diff.Reweighted) > 0 && sameStartDate(previous.StartDate, next.StartDate) {
+		return types.TaxonomyEditResult{}, errors.Wrap(ErrorTaxonomyStartDate, "changing weights needs a start date after the previous version")
+	}
+
+	txHash, err := c.SetTaxonomy(ctx, next)
+	if err != nil {
+		return types.TaxonomyEditResult{}, errors.WithStack(err)
+	}
+
+	return types.TaxonomyEditResult{
+		TxHash:   txHash,
+		Previous: previous,
+		Next:     next,
+		Diff:     diff,
+	}, nil
+}
+
+func sameStartDate(a, b *civil.Date) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// indexOfTaxonomyChild returns the index of the child in the items, or -1 if it's not found
+func indexOfTaxonomyChild(items []types.TaxonomyItem, child types.StreamLocator) int {
+	for i, item := range items {
+		if item.ChildStream.Equals(child) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *ComposedStream) AddChild(ctx context.Context, params types.AddChildParams) (types.TaxonomyEditResult, error) {
+	return c.editTaxonomy(ctx, params.StartDate, func(items []types.TaxonomyItem) ([]types.TaxonomyItem, error) {
+		if indexOfTaxonomyChild(items, params.Child.ChildStream) != -1 {
+			return nil, ErrorTaxonomyChildExists
+		}
+		return append(items, params.Child), nil
+	})
+}
+
+func (c *ComposedStream) RemoveChild(ctx context.Context, params types.RemoveChildParams) (types.TaxonomyEditResult, error) {
+	return c.editTaxonomy(ctx, params.StartDate, func(items []types.TaxonomyItem) ([]types.TaxonomyItem, error) {
+		idx := indexOfTaxonomyChild(items, params.Child)
+		if idx == -1 {
+			return nil, ErrorTaxonomyChildNotFound
+		}
+		return append(items[:idx], items[idx+1:]...), nil
+	})
+}
+
+func (c *ComposedStream) UpdateWeights(ctx context.Context, params types.UpdateWeightsParams) (types.TaxonomyEditResult, error) {
+	return c.editTaxonomy(ctx, params.StartDate, func(items []types.TaxonomyItem) ([]types.TaxonomyItem, error) {
+		for _, weight := range params.Weights {
+			idx := indexOfTaxonomyChild(items, weight.ChildStream)
+			if idx == -1 {
+				return nil, errors.Wrap(ErrorTaxonomyChildNotFound, weight.ChildStream.StreamId.String())
+			}
+			items[idx].Weight = weight.Weight
+		}
+		return items, nil
+	})
+}
+
+type taxonomyRecordRaw struct {
+	ChildStreamId     util.StreamId `json:"child_stream_id"`
+	ChildDataProvider string        `json:"child_data_provider"`
+	Weight            string        `json:"weight"`
+	CreatedAt         int           `json:"created_at"`
+	DisabledAt        *int          `json:"disabled_at"`
+	Version           int           `json:"version"`
+	StartDate         *string       `json:"start_date"`
+}
+
+// getTaxonomyRecords returns every taxonomy row, including disabled ones, as they are
+// also considered by the contract when picking weights. describe_taxonomies only returns
+// enabled rows, without their disabled_at, so the taxonomies table is queried directly
+func (c *ComposedStream) getTaxonomyRecords(ctx context.Context) ([]types.TaxonomyRecord, error) {
+	records, err := c.query(ctx, "SELECT child_stream_id, child_data_provider, weight, created_at, disabled_at, version, start_date FROM taxonomies")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rawRecords, err := DecodeCallResult[taxonomyRecordRaw](records)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	taxonomyRecords := make([]types.TaxonomyRecord, len(rawRecords))
+	for i, r := range rawRecords {
+		dpAddress, err := util.NewEthereumAddressFromString(r.ChildDataProvider)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		weight, err := strconv.ParseFloat(r.Weight, 64)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		var startDate *civil.Date
+		if r.StartDate != nil && *r.StartDate != "" {
+			parsedDate, err := civil.ParseDate(*r.StartDate)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			startDate = &parsedDate
+		}
+
+		taxonomyRecords[i] = types.TaxonomyRecord{
+			ChildStream: types.StreamLocator{
+				StreamId:     r.ChildStreamId,
+				DataProvider: dpAddress,
+			},
+			Weight:     weight,
+			CreatedAt:  r.CreatedAt,
+			DisabledAt: r.DisabledAt,
+			Version:    r.Version,
+			StartDate:  startDate,
+		}
+	}
+
+	return taxonomyRecords, nil
+}
+
+func (c *ComposedStream) GetEffectiveTaxonomy(ctx context.Context, date civil.Date) (types.EffectiveTaxonomy, error) {
+	if !date.IsValid() {
+		return types.EffectiveTaxonomy{}, errors.New(fmt.Sprintf("invalid date: %s", date))
+	}
+
+	records, err := c.getTaxonomyRecords(ctx)
+	if err != nil {
+		return types.EffectiveTaxonomy{}, errors.WithStack(err)
+	}
+
+	return types.EffectiveTaxonomyAt(records, date), nil
+}
+
+func (c *ComposedStream) DescribeTaxonomyTimeline(ctx context.Context) ([]types.TaxonomyTimelineEntry, error) {
+	records, err := c.getTaxonomyRecords(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return types.TaxonomyTimeline(records), nil
+}
@@ -0,0 +1,43 @@
+package contractsapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-sql/civil"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestTaxonomyQueryErrors checks that errors of the queries on the taxonomies table match the contract sentinels.
+func TestTaxonomyQueryErrors(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value_s": "composed"}}), nil
+		},
+		QueryFunc: func(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+			return nil, errors.New("err code = -300, msg = ERROR: wallet not allowed to read (SQLSTATE P0001)")
+		},
+	}
+	stream, err := contractsapi.LoadComposedStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-taxonomy-query-errors"),
+		Deployer: owner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+
+	_, err = stream.GetEffectiveTaxonomy(ctx, civil.Date{Year: 2024, Month: 1, Day: 1})
+	assert.ErrorIs(t, err, types.ErrorWalletNotAllowedToRead)
+
+	_, err = stream.DescribeTaxonomyTimeline(ctx)
+	assert.ErrorIs(t, err, types.ErrorWalletNotAllowedToRead)
+}
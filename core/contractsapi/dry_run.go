@@ -0,0 +1,47 @@
+package contractsapi
+
+import (
+	"bytes"
+	"context"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// checkCaller checks if the caller may run the write procedure, like the contract does
+func (s *Stream) checkCaller(ctx context.Context, method string, caller []byte) error {
+	wallet, err := util.NewEthereumAddressFromBytes(caller)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	switch method {
+	case "init":
+		// init can only be called by the deployer, and only once
+		if !bytes.Equal(caller, s._deployer) {
+			return errors.Wrap(types.ErrorStreamOwnerOnly, method)
+		}
+		if _, err := s.GetType(ctx); err == nil {
+			return types.ErrorContractAlreadyInitialized
+		}
+	case "insert_record":
+		allowed, err := s.CanWrite(ctx, wallet)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !allowed {
+			return types.ErrorWalletNotAllowedToWrite
+		}
+	default:
+		// every other write procedure is owner only
+		isOwner, err := s.isStreamOwner(ctx, wallet)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !isOwner {
+			return errors.Wrap(types.ErrorStreamOwnerOnly, method)
+		}
+	}
+
+	return nil
+}
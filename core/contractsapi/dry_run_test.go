@@ -0,0 +1,89 @@
+package contractsapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-sql/civil"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// dryRunNode answers the views of an initialized primitive stream. Broadcasts fail the test
+func dryRunNode(t *testing.T, allowedWriter bool) *kwiltest.Client {
+	return &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			switch procedure {
+			case "get_metadata":
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value_s": "primitive"}}), nil
+			case "is_wallet_allowed_to_write":
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value": allowedWriter}}), nil
+			}
+			return kwilClientType.NewRecordsFromMaps(nil), nil
+		},
+		ExecuteFunc: func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error) {
+			t.Fatal("dry runs should not broadcast")
+			return nil, nil
+		},
+	}
+}
+
+// TestDryRun checks that dry-run writes run their checks and return the transaction instead of broadcasting it.
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	caller := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+	streamId := util.GenerateStreamId("test-dry-run")
+	records := []types.InsertRecordInput{{DateValue: civil.Date{Year: 2024, Month: 1, Day: 1}, Value: 1}}
+
+	loadStream := func(node *kwiltest.Client, dryRun *transport.DryRun) *contractsapi.PrimitiveStream {
+		client := transport.NewDryRunClient(node, node, caller.Bytes(), dryRun)
+		stream, err := contractsapi.LoadPrimitiveStream(contractsapi.NewStreamOptions{
+			Client:   client,
+			StreamId: streamId,
+			Deployer: caller.Bytes(),
+		})
+		require.NoError(t, err, "Failed to load stream")
+		return stream
+	}
+
+	t.Run("ClientDryRun", func(t *testing.T) {
+		dryRun := transport.NewDryRun()
+		stream := loadStream(dryRunNode(t, true), dryRun)
+
+		txHash, err := stream.InsertRecords(ctx, records)
+		require.NoError(t, err, "Dry run should succeed")
+
+		txs := dryRun.Transactions()
+		if assert.Equal(t, 1, len(txs)) {
+			assert.Equal(t, txHash, txs[0].Hash)
+			assert.Equal(t, "Execute", txs[0].Operation)
+			assert.Equal(t, transactions.PayloadTypeExecute, txs[0].Tx.Body.PayloadType)
+		}
+	})
+
+	t.Run("PermissionChecked", func(t *testing.T) {
+		dryRun := transport.NewDryRun()
+		stream := loadStream(dryRunNode(t, false), dryRun)
+
+		_, err := stream.InsertRecords(ctx, records)
+		assert.True(t, errors.Is(err, types.ErrorWalletNotAllowedToWrite))
+		assert.Empty(t, dryRun.Transactions())
+	})
+
+	t.Run("InputValidated", func(t *testing.T) {
+		dryRun := transport.NewDryRun()
+		stream := loadStream(dryRunNode(t, true), dryRun)
+
+		_, err := stream.InsertRecords(ctx, []types.InsertRecordInput{{DateValue: civil.Date{Year: 2024, Month: 2, Day: 30}}})
+		assert.True(t, errors.Is(err, contractsapi.ErrorInvalidRecordDate))
+		assert.Empty(t, dryRun.Transactions())
+	})
+}
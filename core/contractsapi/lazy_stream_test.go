@@ -0,0 +1,110 @@
+package contractsapi_test
+
+import (
+	"context"
+	"testing"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// schemaNode reports every dataset as missing unless deployed, allowing any read
+func schemaNode(deployed *bool) *kwiltest.Client {
+	return &kwiltest.Client{
+		GetSchemaFunc: func(ctx context.Context, dbid string) (*kwiltypes.Schema, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if !*deployed {
+				return nil, errors.New("dataset not found")
+			}
+			return &kwiltypes.Schema{}, nil
+		},
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value": true}}), nil
+		},
+	}
+}
+
+// TestLazyStreamLoading checks that lazily loaded streams are checked on their first request, and that
+// eager loads use the given context.
+func TestLazyStreamLoading(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+	streamId := util.GenerateStreamId("test-lazy-stream")
+
+	t.Run("LazyLoadChecksOnFirstRequest", func(t *testing.T) {
+		deployed := true
+		node := schemaNode(&deployed)
+		stream, err := contractsapi.LoadStreamContext(ctx, contractsapi.NewStreamOptions{
+			Client:   node,
+			StreamId: streamId,
+			Deployer: owner.Bytes(),
+			Lazy:     true,
+		})
+		require.NoError(t, err, "Failed to load stream")
+		assert.Equal(t, 0, node.Requests("GetSchema"))
+
+		_, err = stream.CanRead(ctx, owner)
+		require.NoError(t, err, "CanRead should succeed")
+		_, err = stream.CanRead(ctx, owner)
+		require.NoError(t, err, "CanRead should succeed")
+		assert.Equal(t, 1, node.Requests("GetSchema"))
+	})
+
+	t.Run("LazyLoadOfMissingStream", func(t *testing.T) {
+		deployed := false
+		stream, err := contractsapi.LoadStreamContext(ctx, contractsapi.NewStreamOptions{
+			Client:   schemaNode(&deployed),
+			StreamId: streamId,
+			Deployer: owner.Bytes(),
+			Lazy:     true,
+		})
+		require.NoError(t, err, "Lazy load should not fail")
+
+		_, err = stream.CanRead(ctx, owner)
+		assert.ErrorIs(t, err, contractsapi.ErrorStreamNotFound)
+	})
+
+	t.Run("EagerLoad", func(t *testing.T) {
+		deployed := false
+		node := schemaNode(&deployed)
+		_, err := contractsapi.LoadStreamContext(ctx, contractsapi.NewStreamOptions{
+			Client:   node,
+			StreamId: streamId,
+			Deployer: owner.Bytes(),
+		})
+		assert.ErrorIs(t, err, contractsapi.ErrorStreamNotFound)
+
+		deployed = true
+		stream, err := contractsapi.LoadStreamContext(ctx, contractsapi.NewStreamOptions{
+			Client:   node,
+			StreamId: streamId,
+			Deployer: owner.Bytes(),
+		})
+		require.NoError(t, err, "Failed to load stream")
+		_, err = stream.CanRead(ctx, owner)
+		require.NoError(t, err, "CanRead should succeed")
+		// the load already checked the stream
+		assert.Equal(t, 2, node.Requests("GetSchema"))
+	})
+
+	t.Run("EagerLoadUsesContext", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		deployed := true
+		_, err := contractsapi.LoadStreamContext(canceledCtx, contractsapi.NewStreamOptions{
+			Client:   schemaNode(&deployed),
+			StreamId: streamId,
+			Deployer: owner.Bytes(),
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,155 @@
+package contractsapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+type metadataRecordRaw struct {
+	RowId       string  `json:"row_id"`
+	MetadataKey string  `json:"metadata_key"`
+	ValueI      *int    `json:"value_i"`
+	ValueF      *string `json:"value_f"`
+	ValueB      *bool   `json:"value_b"`
+	ValueS      *string `json:"value_s"`
+	ValueRef    *string `json:"value_ref"`
+	CreatedAt   int     `json:"created_at"`
+	DisabledAt  *int    `json:"disabled_at"`
+}
+
+// storedType infers the type of the row from the column that is set. It's empty if none is
+func (r metadataRecordRaw) storedType() (types.MetadataType, string) {
+	switch {
+	case r.ValueI != nil:
+		return types.MetadataTypeInt, strconv.Itoa(*r.ValueI)
+	case r.ValueB != nil:
+		return types.MetadataTypeBool, strconv.FormatBool(*r.ValueB)
+	case r.ValueF != nil:
+		return types.MetadataTypeFloat, *r.ValueF
+	case r.ValueS != nil:
+		return types.MetadataTypeString, *r.ValueS
+	case r.ValueRef != nil:
+		return types.MetadataTypeRef, *r.ValueRef
+	default:
+		return "", ""
+	}
+}
+
+func (r metadataRecordRaw) toRecord() (types.MetadataRecord, error) {
+	metadataType, value := r.storedType()
+	if metadataType == "" {
+		return types.MetadataRecord{}, errors.New(fmt.Sprintf("metadata row %s has no value", r.RowId))
+	}
+
+	return types.MetadataRecord{
+		RowId:      r.RowId,
+		Key:        types.MetadataKey(r.MetadataKey),
+		Type:       metadataType,
+		Value:      value,
+		CreatedAt:  r.CreatedAt,
+		DisabledAt: r.DisabledAt,
+	}, nil
+}
+
+// toResult leaves the columns that aren't set with their zero value
+func (r metadataRecordRaw) toResult() getMetadataResult {
+	result := getMetadataResult{
+		RowId:     r.RowId,
+		CreatedAt: r.CreatedAt,
+	}
+	result.Type, _ = r.storedType()
+	if r.ValueI != nil {
+		result.ValueI = *r.ValueI
+	}
+	if r.ValueB != nil {
+		result.ValueB = *r.ValueB
+	}
+	if r.ValueF != nil {
+		result.ValueF = *r.ValueF
+	}
+	if r.ValueS != nil {
+		result.ValueS = *r.ValueS
+	}
+	if r.ValueRef != nil {
+		result.ValueRef = *r.ValueRef
+	}
+	return result
+}
+
+// GetMetadataHistory returns metadata rows, including disabled ones, ordered by creation.
+// get_metadata only returns the enabled rows of a single key, without their metadata_key nor disabled_at,
+// so the metadata table is queried directly
+func (s *Stream) GetMetadataHistory(ctx context.Context, params types.MetadataHistoryParams) (types.MetadataAuditLog, error) {
+	var conditions []string
+	if len(params.Keys) > 0 {
+		keys := make([]string, len(params.Keys))
+		for i, key := range params.Keys {
+			keys[i] = quoteSQLString(key.String())
+		}
+		conditions = append(conditions, fmt.Sprintf("metadata_key IN (%s)", strings.Join(keys, ", ")))
+	}
+	if params.Ref != "" {
+		conditions = append(conditions, fmt.Sprintf("value_ref = %s", quoteSQLString(strings.ToLower(params.Ref))))
+	}
+
+	query := "SELECT row_id, metadata_key, value_i, value_f, value_b, value_s, value_ref, created_at, disabled_at FROM metadata"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at ASC, row_id ASC"
+
+	records, err := s.query(ctx, query)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rawRecords, err := DecodeCallResult[metadataRecordRaw](records)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	auditLog := make(types.MetadataAuditLog, len(rawRecords))
+	for i, rawRecord := range rawRecords {
+		auditLog[i], err = rawRecord.toRecord()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return auditLog, nil
+}
+
+// quoteSQLString quotes a string to be used as a SQL literal
+func quoteSQLString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// GetMetadataAt gets the metadata as it was at a block height
+func (s *Stream) GetMetadataAt(ctx context.Context, height int) (types.MetadataSnapshot, error) {
+	history, err := s.GetMetadataHistory(ctx, types.MetadataHistoryParams{})
+	if err != nil {
+		return types.MetadataSnapshot{}, errors.WithStack(err)
+	}
+
+	snapshot := types.NewMetadataSnapshot(history, height)
+
+	// the allowlist stores DBIDs, so we resolve them as GetAllowedComposeStreams does. Streams allowed
+	// in the past may have been dropped since, which shouldn't hide the rest of the metadata
+	snapshot.ComposeStreams = make(map[string]types.StreamLocator)
+	for _, dbid := range snapshot.AllowedComposeStreamDBIDs() {
+		locator, err := s.streamLocatorFromDBID(ctx, dbid)
+		if err != nil {
+			s._logger.Debug("allowed compose stream can't be resolved, keeping its DBID",
+				"dbid", dbid, "error", err)
+			continue
+		}
+		snapshot.ComposeStreams[dbid] = locator
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,50 @@
+package contractsapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestMetadataHistoryQuery checks the filters of the query on the metadata table, and that its errors
+// match the contract sentinels.
+func TestMetadataHistoryQuery(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+
+	var queries []string
+	var queryErr error
+	node := &kwiltest.Client{
+		QueryFunc: func(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+			queries = append(queries, query)
+			return kwilClientType.NewRecordsFromMaps(nil), queryErr
+		},
+	}
+	stream, err := contractsapi.LoadStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-metadata-history-query"),
+		Deployer: owner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+
+	_, err = stream.GetMetadataHistory(ctx, types.MetadataHistoryParams{
+		Keys: []types.MetadataKey{types.AllowReadWalletKey, types.ReadonlyKey},
+		Ref:  "0xAB'CD",
+	})
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "metadata_key IN ('allow_read_wallet', 'readonly_key')")
+	assert.Contains(t, queries[0], "value_ref = '0xab''cd'")
+
+	queryErr = errors.New("err code = -300, msg = ERROR: wallet not allowed to read (SQLSTATE P0001)")
+	_, err = stream.GetMetadataHistory(ctx, types.MetadataHistoryParams{})
+	assert.ErrorIs(t, err, types.ErrorWalletNotAllowedToRead)
+}
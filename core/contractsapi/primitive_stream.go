@@ -16,8 +16,10 @@ var _ types.IPrimitiveStream = (*PrimitiveStream)(nil)
 
 var (
 	ErrorStreamNotPrimitive = errors.New("stream is not a primitive stream")
+	ErrorInvalidRecordDate  = errors.New("invalid record date")
 )
 
+// PrimitiveStreamFromStream converts the stream. The result shares the cache of the stream, i.e. its type and owner
 func PrimitiveStreamFromStream(stream Stream) (*PrimitiveStream, error) {
 	return &PrimitiveStream{
 		Stream: stream,
@@ -25,7 +27,11 @@ func PrimitiveStreamFromStream(stream Stream) (*PrimitiveStream, error) {
 }
 
 func LoadPrimitiveStream(options NewStreamOptions) (*PrimitiveStream, error) {
-	stream, err := LoadStream(options)
+	return LoadPrimitiveStreamContext(context.Background(), options)
+}
+
+func LoadPrimitiveStreamContext(ctx context.Context, options NewStreamOptions) (*PrimitiveStream, error) {
+	stream, err := LoadStreamContext(ctx, options)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -62,7 +68,7 @@ func (p *PrimitiveStream) checkedExecute(ctx context.Context, method string, arg
 		return transactions.TxHash{}, errors.WithStack(err)
 	}
 
-	return p._client.Execute(ctx, p.DBID, method, args)
+	return p.execute(ctx, method, args)
 }
 
 func (p *PrimitiveStream) InsertRecords(ctx context.Context, inputs []types.InsertRecordInput) (transactions.TxHash, error) {
@@ -73,6 +79,9 @@ func (p *PrimitiveStream) InsertRecords(ctx context.Context, inputs []types.Inse
 
 	var args [][]any
 	for _, input := range inputs {
+		if !input.DateValue.IsValid() {
+			return transactions.TxHash{}, errors.Wrap(ErrorInvalidRecordDate, input.DateValue.String())
+		}
 
 		dateStr := input.DateValue.String()
 
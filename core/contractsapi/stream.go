@@ -9,22 +9,26 @@ import (
 	"github.com/kwilteam/kwil-db/core/types/transactions"
 	kwilUtils "github.com/kwilteam/kwil-db/core/utils"
 	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/logging"
+	"github.com/trufnetwork/sdk-go/core/transport"
 	tntypes "github.com/trufnetwork/sdk-go/core/types"
 	"github.com/trufnetwork/sdk-go/core/util"
+	"log/slog"
 	"strings"
 )
 
 // ## Initializations
 
 type Stream struct {
-	StreamId     util.StreamId
-	_type        tntypes.StreamType
-	_deployer    []byte
-	_owner       []byte
-	DBID         string
-	_client      client.Client
-	_initialized bool
-	_deployed    bool
+	StreamId  util.StreamId
+	_deployer []byte
+	// _signer signs the transactions sent through _client, if known
+	_signer []byte
+	DBID    string
+	_client client.Client
+	_logger *slog.Logger
+	// _state is shared with the streams converted from this one
+	_state *streamState
 }
 
 var _ tntypes.IStream = (*Stream)(nil)
@@ -33,6 +37,13 @@ type NewStreamOptions struct {
 	Client   client.Client
 	StreamId util.StreamId
 	Deployer []byte
+	// Signer optional. The identity signing the transactions of the client, needed to grant compose on children
+	Signer []byte
+	// Logger optional. Nothing is logged if not set
+	Logger *slog.Logger
+	// Lazy skips the request that checks if the stream is deployed, so the handle is built without I/O.
+	// Loaded streams are then checked on their first request instead
+	Lazy bool
 }
 
 var (
@@ -43,6 +54,11 @@ var (
 
 // NewStream creates a new stream, it is straightforward and only requires the stream id and the deployer
 func NewStream(options NewStreamOptions) (*Stream, error) {
+	return NewStreamContext(context.Background(), options)
+}
+
+// NewStreamContext is NewStream, checking that the stream isn't deployed yet with the given context
+func NewStreamContext(ctx context.Context, options NewStreamOptions) (*Stream, error) {
 	optClient := options.Client
 	streamId := options.StreamId
 	deployer := options.Deployer
@@ -54,21 +70,32 @@ func NewStream(options NewStreamOptions) (*Stream, error) {
 
 	dbid := kwilUtils.GenerateDBID(streamId.String(), deployer)
 	// check if the stream is found
-	if _, err := optClient.GetSchema(context.Background(), dbid); err == nil {
-		// if there's no error, it means the stream is already deployed
-		return nil, ErrorDatasetExists
+	if !options.Lazy {
+		if _, err := optClient.GetSchema(ctx, dbid); err == nil {
+			// if there's no error, it means the stream is already deployed
+			return nil, ErrorDatasetExists
+		}
 	}
 
 	return &Stream{
 		StreamId:  streamId,
 		_deployer: deployer,
+		_signer:   options.Signer,
 		DBID:      dbid,
 		_client:   optClient,
+		_logger:   logging.OrDiscard(options.Logger),
+		_state:    &streamState{},
 	}, nil
 }
 
 // LoadStream loads an existing stream, so it also checks if the stream is deployed
 func LoadStream(options NewStreamOptions) (*Stream, error) {
+	return LoadStreamContext(context.Background(), options)
+}
+
+// LoadStreamContext is LoadStream, checking if the stream is deployed with the given context.
+// With options.Lazy, nothing is checked until the first request of the stream
+func LoadStreamContext(ctx context.Context, options NewStreamOptions) (*Stream, error) {
 	streamId := options.StreamId
 	deployer := options.Deployer
 	optClient := options.Client
@@ -78,22 +105,32 @@ func LoadStream(options NewStreamOptions) (*Stream, error) {
 	}
 
 	dbid := kwilUtils.GenerateDBID(streamId.String(), deployer)
-	// check if the stream is found
-	if _, err := optClient.GetSchema(context.Background(), dbid); err != nil {
-		// if err contains "dataset not found", it means the stream is not deployed, then we return our error
-		if strings.Contains(err.Error(), "dataset not found") {
-			return nil, ErrorStreamNotFound
-		}
-
-		return nil, errors.WithStack(err)
-	}
-
-	return &Stream{
+	stream := &Stream{
 		StreamId:  streamId,
 		_deployer: options.Deployer,
+		_signer:   options.Signer,
 		DBID:      dbid,
 		_client:   optClient,
-	}, nil
+		_logger:   logging.OrDiscard(options.Logger),
+		_state:    &streamState{},
+	}
+
+	if options.Lazy {
+		return stream, nil
+	}
+
+	// check if the stream is found
+	if err := stream.checkDeployed(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return stream, nil
+}
+
+// Refresh drops what the stream cached, such as its type and owner, so it's fetched again when needed.
+// Streams converted from one another share their cache, so they are refreshed too
+func (s *Stream) Refresh() {
+	s._state.reset()
 }
 
 func (s *Stream) ToComposedStream() (*ComposedStream, error) {
@@ -105,12 +142,12 @@ func (s *Stream) ToPrimitiveStream() (*PrimitiveStream, error) {
 }
 
 func (s *Stream) GetSchema(ctx context.Context) (*types.Schema, error) {
-	return s._client.GetSchema(ctx, s.DBID)
+	return s._client.GetSchema(transport.WithStreamId(ctx, s.StreamId.String()), s.DBID)
 }
 
 func (s *Stream) GetType(ctx context.Context) (tntypes.StreamType, error) {
-	if s._type != "" {
-		return s._type, nil
+	if streamType := s._state.getType(); streamType != "" {
+		return streamType, nil
 	}
 
 	values, err := s.getMetadata(ctx, getMetadataParams{
@@ -126,25 +163,23 @@ func (s *Stream) GetType(ctx context.Context) (tntypes.StreamType, error) {
 		return "", errors.New("no type found, check if the stream is initialized")
 	}
 
+	var streamType tntypes.StreamType
 	switch values[0].ValueS {
 	case "composed":
-		s._type = tntypes.StreamTypeComposed
+		streamType = tntypes.StreamTypeComposed
 	case "primitive":
-		s._type = tntypes.StreamTypePrimitive
+		streamType = tntypes.StreamTypePrimitive
 	default:
 		return "", errors.New(fmt.Sprintf("unknown stream type: %s", values[0].ValueS))
 	}
 
-	if s._type == "" {
-		return "", errors.New("stream type is not set")
-	}
-
-	return s._type, nil
+	s._state.setType(streamType)
+	return streamType, nil
 }
 
 func (s *Stream) GetStreamOwner(ctx context.Context) ([]byte, error) {
-	if s._owner != nil {
-		return s._owner, nil
+	if owner := s._state.getOwner(); owner != nil {
+		return owner, nil
 	}
 
 	values, err := s.getMetadata(ctx, getMetadataParams{
@@ -160,16 +195,17 @@ func (s *Stream) GetStreamOwner(ctx context.Context) ([]byte, error) {
 		return nil, errors.New("no owner found (is the stream initialized?)")
 	}
 
-	s._owner, err = hex.DecodeString(values[0].ValueRef)
+	owner, err := hex.DecodeString(values[0].ValueRef)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	return s._owner, nil
+	s._state.setOwner(owner)
+	return owner, nil
 }
 
 func (s *Stream) checkInitialized(ctx context.Context) error {
-	if s._initialized {
+	if s._state.isInitialized() {
 		return nil
 	}
 
@@ -186,32 +222,67 @@ func (s *Stream) checkInitialized(ctx context.Context) error {
 		return errors.Wrap(err, "check if the stream is initialized")
 	}
 
-	s._initialized = true
+	s._state.setInitialized()
 
 	return nil
 }
 
 func (s *Stream) checkDeployed(ctx context.Context) error {
-	if s._deployed {
+	if s._state.isDeployed() {
 		return nil
 	}
 
 	_, err := s.GetSchema(ctx)
 	if err != nil {
+		// if err contains "dataset not found", it means the stream is not deployed, then we return our error
+		if strings.Contains(err.Error(), "dataset not found") {
+			return ErrorStreamNotFound
+		}
+
 		return errors.Wrap(err, "check if the stream is deployed")
 	}
 
-	s._deployed = true
+	s._state.setDeployed()
 
 	return nil
 }
 
+// call runs a view procedure. Known contract errors are mapped to their sentinels
 func (s *Stream) call(ctx context.Context, method string, args []any) (*client.Records, error) {
-	return s._client.Call(ctx, s.DBID, method, args)
+	// lazily loaded streams are checked on their first request
+	if err := s.checkDeployed(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	records, err := s._client.Call(transport.WithStreamId(ctx, s.StreamId.String()), s.DBID, method, args)
+	return records, tntypes.MapContractError(err)
+}
+
+// query runs a read-only SQL query against the stream tables. It's used where procedures
+// don't expose the needed rows, such as disabled ones. Known contract errors are mapped to their sentinels
+func (s *Stream) query(ctx context.Context, query string) (*client.Records, error) {
+	if err := s.checkDeployed(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	records, err := s._client.Query(transport.WithStreamId(ctx, s.StreamId.String()), s.DBID, query)
+	return records, tntypes.MapContractError(err)
 }
 
 func (s *Stream) execute(ctx context.Context, method string, args [][]any) (transactions.TxHash, error) {
-	return s._client.Execute(ctx, s.DBID, method, args)
+	if err := s.checkDeployed(ctx); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// a dry run is never executed by the node, so we check the caller as the contract would
+	if caller := transport.DryRunCaller(ctx, s._client); caller != nil {
+		if err := s.checkCaller(ctx, method, caller); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	txHash, err := s._client.Execute(transport.WithStreamId(ctx, s.StreamId.String()), s.DBID, method, args)
+	return txHash, tntypes.MapContractError(err)
 }
 
 // except for init, all write methods should be checked for initialization
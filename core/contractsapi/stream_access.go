@@ -0,0 +1,92 @@
+package contractsapi
+
+import (
+	"context"
+
+	"github.com/kwilteam/kwil-db/core/utils"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// boolValueResult is the result of procedures that return `(value bool)`
+type boolValueResult struct {
+	Value bool `json:"value"`
+}
+
+// boolResultResult is the result of procedures that return `(result bool)`
+type boolResultResult struct {
+	Result bool `json:"result"`
+}
+
+func (s *Stream) CanRead(ctx context.Context, wallet util.EthereumAddress) (bool, error) {
+	records, err := s.call(ctx, "is_wallet_allowed_to_read", []any{wallet.Address()})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	results, err := DecodeCallResult[boolValueResult](records)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return len(results) > 0 && results[0].Value, nil
+}
+
+func (s *Stream) CanWrite(ctx context.Context, wallet util.EthereumAddress) (bool, error) {
+	streamType, err := s.GetType(ctx)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	// composed streams have no records to insert, every write procedure is owner only
+	if streamType == types.StreamTypeComposed {
+		return s.isStreamOwner(ctx, wallet)
+	}
+
+	records, err := s.call(ctx, "is_wallet_allowed_to_write", []any{wallet.Address()})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	results, err := DecodeCallResult[boolValueResult](records)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return len(results) > 0 && results[0].Value, nil
+}
+
+func (s *Stream) CanCompose(ctx context.Context, locator types.StreamLocator) (bool, error) {
+	dbid := utils.GenerateDBID(locator.StreamId.String(), locator.DataProvider.Bytes())
+
+	records, err := s.call(ctx, "is_stream_allowed_to_compose", []any{dbid})
+	if err != nil {
+		// the procedure raises an error instead of returning false
+		if errors.Is(err, types.ErrorStreamNotAllowedToCompose) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+
+	results, err := DecodeCallResult[boolValueResult](records)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return len(results) > 0 && results[0].Value, nil
+}
+
+func (s *Stream) isStreamOwner(ctx context.Context, wallet util.EthereumAddress) (bool, error) {
+	records, err := s.call(ctx, "is_stream_owner", []any{wallet.Address()})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	results, err := DecodeCallResult[boolResultResult](records)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return len(results) > 0 && results[0].Result, nil
+}
@@ -0,0 +1,176 @@
+package contractsapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/kwilteam/kwil-db/core/utils"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+func (s *Stream) PlanACL(ctx context.Context, desired types.StreamACL) (types.ACLPlan, error) {
+	var plan types.ACLPlan
+
+	if err := s.planVisibility(ctx, &plan, types.ReadVisibilityKey, desired.ReadVisibility); err != nil {
+		return types.ACLPlan{}, errors.WithStack(err)
+	}
+	if err := s.planVisibility(ctx, &plan, types.ComposeVisibilityKey, desired.ComposeVisibility); err != nil {
+		return types.ACLPlan{}, errors.WithStack(err)
+	}
+
+	if desired.AllowedReadWallets != nil {
+		if err := s.planAllowlist(ctx, &plan, types.AllowReadWalletKey, walletRefs(desired.AllowedReadWallets)); err != nil {
+			return types.ACLPlan{}, errors.WithStack(err)
+		}
+	}
+	if desired.AllowedWriteWallets != nil {
+		if err := s.planAllowlist(ctx, &plan, types.AllowWriteWalletKey, walletRefs(desired.AllowedWriteWallets)); err != nil {
+			return types.ACLPlan{}, errors.WithStack(err)
+		}
+	}
+	if desired.AllowedComposeStreams != nil {
+		refs := make([]string, len(desired.AllowedComposeStreams))
+		for i, locator := range desired.AllowedComposeStreams {
+			refs[i] = utils.GenerateDBID(locator.StreamId.String(), locator.DataProvider.Bytes())
+		}
+		if err := s.planAllowlist(ctx, &plan, types.AllowComposeStreamKey, refs); err != nil {
+			return types.ACLPlan{}, errors.WithStack(err)
+		}
+	}
+
+	return plan, nil
+}
+
+func (s *Stream) ApplyACLPlan(ctx context.Context, plan types.ACLPlan) ([]transactions.TxHash, error) {
+	var txHashes []transactions.TxHash
+
+	if len(plan.Inserts) > 0 {
+		inputs := make([]types.MetadataInput, len(plan.Inserts))
+		for i, change := range plan.Inserts {
+			value, err := aclMetadataValue(change)
+			if err != nil {
+				return txHashes, errors.WithStack(err)
+			}
+			inputs[i] = types.MetadataInput{Key: change.Key, Value: value}
+		}
+
+		txHash, err := s.BatchInsertMetadata(ctx, inputs)
+		if err != nil {
+			return txHashes, errors.WithStack(err)
+		}
+		txHashes = append(txHashes, txHash)
+	}
+
+	if len(plan.Disables) > 0 {
+		rowIds := make([]string, len(plan.Disables))
+		for i, change := range plan.Disables {
+			rowIds[i] = change.RowId
+		}
+
+		txHash, err := s.BatchDisableMetadata(ctx, rowIds)
+		if err != nil {
+			return txHashes, errors.WithStack(err)
+		}
+		txHashes = append(txHashes, txHash)
+	}
+
+	return txHashes, nil
+}
+
+// planVisibility adds a new visibility row if the desired one differs from the latest.
+// Without a row the contracts treat the stream as public
+func (s *Stream) planVisibility(ctx context.Context, plan *types.ACLPlan, key types.MetadataKey, desired *util.VisibilityEnum) error {
+	if desired == nil {
+		return nil
+	}
+
+	results, err := s.getMetadata(ctx, getMetadataParams{
+		Key:        key,
+		OnlyLatest: true,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	current := util.PublicVisibility
+	if len(results) > 0 {
+		current = util.VisibilityEnum(results[0].ValueI)
+	}
+	if current == *desired {
+		return nil
+	}
+
+	plan.Inserts = append(plan.Inserts, types.ACLChange{
+		Key:   key,
+		Value: strconv.Itoa(int(*desired)),
+	})
+	return nil
+}
+
+// planAllowlist grants the desired refs that are missing and disables every row of refs not desired
+func (s *Stream) planAllowlist(ctx context.Context, plan *types.ACLPlan, key types.MetadataKey, desired []string) error {
+	results, err := s.getMetadata(ctx, getMetadataParams{
+		Key: key,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	desiredSet := make(map[string]bool)
+	for _, ref := range desired {
+		desiredSet[strings.ToLower(ref)] = true
+	}
+
+	currentSet := make(map[string]bool)
+	for _, result := range results {
+		ref := strings.ToLower(result.ValueRef)
+		currentSet[ref] = true
+		if !desiredSet[ref] {
+			plan.Disables = append(plan.Disables, types.ACLChange{
+				Key:   key,
+				Value: ref,
+				RowId: result.RowId,
+			})
+		}
+	}
+
+	for _, ref := range desired {
+		ref = strings.ToLower(ref)
+		if currentSet[ref] {
+			continue
+		}
+		// avoid granting twice if the desired list has duplicates
+		currentSet[ref] = true
+		plan.Inserts = append(plan.Inserts, types.ACLChange{
+			Key:   key,
+			Value: ref,
+		})
+	}
+
+	return nil
+}
+
+func aclMetadataValue(change types.ACLChange) (types.MetadataValue, error) {
+	switch change.Key.GetType() {
+	case types.MetadataTypeInt:
+		value, err := strconv.Atoi(change.Value)
+		if err != nil {
+			return types.MetadataValue{}, errors.WithStack(err)
+		}
+		return types.NewMetadataValue(value), nil
+	default:
+		return types.NewRefMetadataValue(change.Value), nil
+	}
+}
+
+func walletRefs(wallets []util.EthereumAddress) []string {
+	refs := make([]string, len(wallets))
+	for i, wallet := range wallets {
+		refs[i] = wallet.Address()
+	}
+	return refs
+}
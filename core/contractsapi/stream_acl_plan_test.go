@@ -0,0 +1,46 @@
+package contractsapi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestPlanACLDefaults checks that planning compares against the defaults the contracts use when a key has
+// no row. The fake node has no metadata at all
+func TestPlanACLDefaults(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+
+	stream, err := contractsapi.LoadStream(contractsapi.NewStreamOptions{
+		Client:   &kwiltest.Client{},
+		StreamId: util.GenerateStreamId("test-plan-acl-defaults"),
+		Deployer: owner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+
+	t.Run("PublicIsTheDefault", func(t *testing.T) {
+		public := util.PublicVisibility
+		plan, err := stream.PlanACL(ctx, types.StreamACL{
+			ReadVisibility:    &public,
+			ComposeVisibility: &public,
+		})
+		require.NoError(t, err, "Failed to plan ACL")
+		assert.True(t, plan.IsEmpty(), "Unexpected changes: %s", plan)
+	})
+
+	t.Run("PrivateIsInserted", func(t *testing.T) {
+		private := util.PrivateVisibility
+		plan, err := stream.PlanACL(ctx, types.StreamACL{ReadVisibility: &private})
+		require.NoError(t, err, "Failed to plan ACL")
+		if assert.Equal(t, 1, len(plan.Inserts)) {
+			assert.Equal(t, types.ReadVisibilityKey, plan.Inserts[0].Key)
+		}
+	})
+}
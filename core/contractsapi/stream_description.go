@@ -0,0 +1,86 @@
+package contractsapi
+
+import (
+	"context"
+	"slices"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+var ErrorEmptyDescription = errors.New("description has no fields set")
+
+// SetDescription inserts the non-empty fields of the description in a single transaction.
+// Empty fields are left untouched, use ClearDescriptionFields to unset them
+func (s *Stream) SetDescription(ctx context.Context, description types.StreamDescription) (transactions.TxHash, error) {
+	fields := description.Fields()
+
+	var inputs []types.MetadataInput
+	// iterate the keys, as map order is random
+	for _, key := range types.DescriptionMetadataKeys {
+		if fields[key] == "" {
+			continue
+		}
+		inputs = append(inputs, types.MetadataInput{
+			Key:   key,
+			Value: types.NewMetadataValue(fields[key]),
+		})
+	}
+
+	if len(inputs) == 0 {
+		return transactions.TxHash{}, ErrorEmptyDescription
+	}
+
+	return s.BatchInsertMetadata(ctx, inputs)
+}
+
+// ClearDescriptionFields unsets the fields stored under the given keys, by disabling every row of them,
+// in a single transaction. The hash is nil if none of the fields was set
+func (s *Stream) ClearDescriptionFields(ctx context.Context, keys ...types.MetadataKey) (transactions.TxHash, error) {
+	var rowIds []string
+	for _, key := range keys {
+		if !slices.Contains(types.DescriptionMetadataKeys, key) {
+			return nil, errors.Errorf("%s is not a description key", key)
+		}
+
+		// older rows would show up again if only the latest was disabled
+		rows, err := s.getMetadataRows(ctx, getMetadataParams{Key: key})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, row := range rows {
+			rowIds = append(rowIds, row.RowId)
+		}
+	}
+
+	if len(rowIds) == 0 {
+		return nil, nil
+	}
+
+	return s.BatchDisableMetadata(ctx, rowIds)
+}
+
+// GetDescription reads the latest row of each description key
+func (s *Stream) GetDescription(ctx context.Context) (types.StreamDescription, error) {
+	var records []types.MetadataRecord
+	for _, key := range types.DescriptionMetadataKeys {
+		rows, err := s.getMetadataRows(ctx, getMetadataParams{
+			Key:        key,
+			OnlyLatest: true,
+		})
+		if err != nil {
+			return types.StreamDescription{}, errors.WithStack(err)
+		}
+
+		for _, row := range rows {
+			record, err := row.toRecord()
+			if err != nil {
+				return types.StreamDescription{}, errors.WithStack(err)
+			}
+			records = append(records, record)
+		}
+	}
+
+	return types.StreamDescriptionFromMetadata(records), nil
+}
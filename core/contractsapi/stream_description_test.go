@@ -0,0 +1,74 @@
+package contractsapi_test
+
+import (
+	"context"
+	"testing"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestStreamDescriptionFields checks that the description is read from the latest row of each key,
+// and that clearing a field disables every row of it.
+func TestStreamDescriptionFields(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+
+	// enabled rows of a primitive stream, most recent first
+	rows := map[string][]map[string]any{
+		"type":      {{"row_id": "1", "value_s": "primitive", "created_at": 1}},
+		"unit":      {{"row_id": "3", "value_s": "EUR", "created_at": 3}, {"row_id": "2", "value_s": "USD", "created_at": 2}},
+		"frequency": {{"row_id": "4", "value_s": "daily", "created_at": 4}},
+	}
+	var disabled [][]any
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			keyRows := rows[inputs[0].(string)]
+			if inputs[1].(bool) && len(keyRows) > 0 {
+				keyRows = keyRows[:1]
+			}
+			return kwilClientType.NewRecordsFromMaps(keyRows), nil
+		},
+		ExecuteFunc: func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error) {
+			assert.Equal(t, "disable_metadata", action)
+			disabled = tuples
+			return transactions.TxHash{1}, nil
+		},
+	}
+	stream, err := contractsapi.LoadStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-stream-description"),
+		Deployer: owner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+
+	t.Run("LatestRowWins", func(t *testing.T) {
+		description, err := stream.GetDescription(ctx)
+		require.NoError(t, err, "Failed to get description")
+		assert.Equal(t, types.StreamDescription{Unit: "EUR", Frequency: types.FrequencyDaily}, description)
+	})
+
+	t.Run("ClearDisablesEveryRow", func(t *testing.T) {
+		txHash, err := stream.ClearDescriptionFields(ctx, types.UnitKey, types.DisplayNameKey)
+		require.NoError(t, err, "Failed to clear description fields")
+		assert.Equal(t, transactions.TxHash{1}, txHash)
+		assert.Equal(t, [][]any{{"3"}, {"2"}}, disabled)
+	})
+
+	t.Run("ClearUnsetFields", func(t *testing.T) {
+		txHash, err := stream.ClearDescriptionFields(ctx, types.DisplayNameKey)
+		require.NoError(t, err, "Clearing unset fields should not fail")
+		assert.Nil(t, txHash)
+	})
+
+	t.Run("ClearOtherKeys", func(t *testing.T) {
+		_, err := stream.ClearDescriptionFields(ctx, types.ReadVisibilityKey)
+		assert.Error(t, err, "only description keys can be cleared")
+	})
+}
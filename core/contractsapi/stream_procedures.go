@@ -13,35 +13,39 @@ import (
 
 // ## View only procedures
 
-type getMetadataParams struct {
-	Key        types.MetadataKey
-	OnlyLatest bool
-	// optional. Gets metadata with ref value equal to the given value
-	Ref string
-}
+type getMetadataParams = types.GetMetadataParams
 
 type getMetadataResult struct {
-	RowId  string `json:"row_id"`
-	ValueI int    `json:"value_i"`
-	ValueB bool   `json:"value_b"`
-	// TODO: uncomment when supported
-	// ValueF    float64 `json:"value_f"`
-	ValueS    string `json:"value_s"`
-	ValueRef  string `json:"value_ref"`
-	CreatedAt int    `json:"created_at"`
+	RowId  string
+	ValueI int
+	ValueB bool
+	// ValueF is a decimal(36,18), encoded as string
+	ValueF    string
+	ValueS    string
+	ValueRef  string
+	CreatedAt int
+	// Type is the column the value is stored in
+	Type types.MetadataType
 }
 
 // GetValueByKey returns the value of the metadata by its key
 // I.e. if we expect an int from `ComposeVisibility`, we can call this function
-// to get `valueI` from the result
+// to get `valueI` from the result. As on inserts, well-known keys have a fixed type,
+// and custom keys have the type they were stored with
 func (g getMetadataResult) GetValueByKey(t types.MetadataKey) (any, error) {
-	metadataType := t.GetType()
+	metadataType := t.TypeForStored(g.Type)
 
 	switch metadataType {
 	case types.MetadataTypeInt:
 		return g.ValueI, nil
 	case types.MetadataTypeBool:
 		return g.ValueB, nil
+	case types.MetadataTypeFloat:
+		value, _, err := apd.NewFromString(g.ValueF)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return value, nil
 	case types.MetadataTypeString:
 		return g.ValueS, nil
 	case types.MetadataTypeRef:
@@ -62,8 +66,8 @@ func addArgOrNull(oldArgs []any, newArg any, nullIfZero bool) []any {
 	return append(oldArgs, newArg)
 }
 
-func (s *Stream) getMetadata(ctx context.Context, params getMetadataParams) ([]getMetadataResult, error) {
-
+// getMetadataRows calls get_metadata. Every metadata reader goes through it
+func (s *Stream) getMetadataRows(ctx context.Context, params getMetadataParams) ([]metadataRecordRaw, error) {
 	var args []any
 
 	args = addArgOrNull(args, params.Key.String(), false)
@@ -76,20 +80,59 @@ func (s *Stream) getMetadata(ctx context.Context, params getMetadataParams) ([]g
 		return nil, errors.WithStack(err)
 	}
 
-	return DecodeCallResult[getMetadataResult](res)
+	// the nullable columns tell which type each row is stored with
+	rawRecords, err := DecodeCallResult[metadataRecordRaw](res)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range rawRecords {
+		rawRecords[i].MetadataKey = params.Key.String()
+	}
+	return rawRecords, nil
 }
 
-// ## Write procedures
+func (s *Stream) getMetadata(ctx context.Context, params getMetadataParams) ([]getMetadataResult, error) {
+	rawRecords, err := s.getMetadataRows(ctx, params)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	results := make([]getMetadataResult, len(rawRecords))
+	for i, rawRecord := range rawRecords {
+		results[i] = rawRecord.toResult()
+	}
+	return results, nil
+}
 
-type metadataInput struct {
-	Key   types.MetadataKey
-	Value types.MetadataValue
+// GetMetadata gets the enabled rows of a key, most recent first, with their values typed after the column they are stored in
+func (s *Stream) GetMetadata(ctx context.Context, params types.GetMetadataParams) ([]types.MetadataRecord, error) {
+	rawRecords, err := s.getMetadataRows(ctx, params)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	records := make([]types.MetadataRecord, len(rawRecords))
+	for i, rawRecord := range rawRecords {
+		records[i], err = rawRecord.toRecord()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return records, nil
 }
 
-func (s *Stream) batchInsertMetadata(ctx context.Context, inputs []metadataInput) (transactions.TxHash, error) {
+// ## Write procedures
+
+// BatchInsertMetadata inserts many rows in a single transaction
+func (s *Stream) BatchInsertMetadata(ctx context.Context, inputs []types.MetadataInput) (transactions.TxHash, error) {
 	var tuples [][]any
 	for _, input := range inputs {
-		valType := input.Key.GetType()
+		valType, err := input.Key.TypeForValue(input.Value)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		valStr, err := valType.StringFromValue(input.Value)
 		if err != nil {
 			return nil, errors.WithStack(err)
@@ -101,12 +144,22 @@ func (s *Stream) batchInsertMetadata(ctx context.Context, inputs []metadataInput
 	return s.checkedExecute(ctx, "insert_metadata", tuples)
 }
 
-func (s *Stream) insertMetadata(ctx context.Context, key types.MetadataKey, value types.MetadataValue) (transactions.TxHash, error) {
-	return s.batchInsertMetadata(ctx, []metadataInput{{key, value}})
+func (s *Stream) InsertMetadata(ctx context.Context, key types.MetadataKey, value types.MetadataValue) (transactions.TxHash, error) {
+	return s.BatchInsertMetadata(ctx, []types.MetadataInput{{Key: key, Value: value}})
 }
 
-func (s *Stream) disableMetadata(ctx context.Context, rowId string) (transactions.TxHash, error) {
-	return s.checkedExecute(ctx, "disable_metadata", [][]any{{rowId}})
+func (s *Stream) DisableMetadata(ctx context.Context, rowId string) (transactions.TxHash, error) {
+	return s.BatchDisableMetadata(ctx, []string{rowId})
+}
+
+// BatchDisableMetadata disables many rows in a single transaction
+func (s *Stream) BatchDisableMetadata(ctx context.Context, rowIds []string) (transactions.TxHash, error) {
+	var tuples [][]any
+	for _, rowId := range rowIds {
+		tuples = append(tuples, []any{rowId})
+	}
+
+	return s.checkedExecute(ctx, "disable_metadata", tuples)
 }
 
 func (s *Stream) InitializeStream(ctx context.Context) (transactions.TxHash, error) {
@@ -0,0 +1,76 @@
+package contractsapi
+
+import (
+	"bytes"
+	tntypes "github.com/trufnetwork/sdk-go/core/types"
+	"sync"
+)
+
+// streamState is what a stream handle learned about its stream. Handles converted from one another
+// share it, and it's safe to use from many goroutines
+type streamState struct {
+	mu          sync.RWMutex
+	streamType  tntypes.StreamType
+	owner       []byte
+	initialized bool
+	deployed    bool
+}
+
+func (st *streamState) getType() tntypes.StreamType {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.streamType
+}
+
+func (st *streamState) setType(streamType tntypes.StreamType) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.streamType = streamType
+}
+
+// getOwner returns a copy of the owner, so callers can't change the cached one
+func (st *streamState) getOwner() []byte {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return bytes.Clone(st.owner)
+}
+
+func (st *streamState) setOwner(owner []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.owner = bytes.Clone(owner)
+}
+
+func (st *streamState) isInitialized() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.initialized
+}
+
+func (st *streamState) setInitialized() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.initialized = true
+}
+
+func (st *streamState) isDeployed() bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.deployed
+}
+
+func (st *streamState) setDeployed() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.deployed = true
+}
+
+// reset forgets everything, so it's fetched again on the next requests
+func (st *streamState) reset() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.streamType = ""
+	st.owner = nil
+	st.initialized = false
+	st.deployed = false
+}
@@ -0,0 +1,91 @@
+package contractsapi_test
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestStreamState checks that streams cache their metadata safely across goroutines, that converted
+// streams share it, and that Refresh drops it.
+func TestStreamState(t *testing.T) {
+	ctx := context.Background()
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+	// answers the metadata of an initialized primitive stream, counting the metadata requests
+	var metadataRequests atomic.Int32
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			if procedure == "get_metadata" {
+				metadataRequests.Add(1)
+			}
+			return kwilClientType.NewRecordsFromMaps([]map[string]any{{
+				"value_s":   "primitive",
+				"value_ref": hex.EncodeToString(owner.Bytes()),
+			}}), nil
+		},
+	}
+
+	stream, err := contractsapi.LoadStream(contractsapi.NewStreamOptions{
+		Client:   node,
+		StreamId: util.GenerateStreamId("test-stream-state"),
+		Deployer: owner.Bytes(),
+	})
+	require.NoError(t, err, "Failed to load stream")
+	primitiveStream, err := stream.ToPrimitiveStream()
+	require.NoError(t, err, "Failed to convert stream")
+	composedStream, err := stream.ToComposedStream()
+	require.NoError(t, err, "Failed to convert stream")
+
+	t.Run("ConversionsShareCache", func(t *testing.T) {
+		streamType, err := stream.GetType(ctx)
+		require.NoError(t, err, "Failed to get type")
+		assert.Equal(t, types.StreamTypePrimitive, streamType)
+
+		_, err = primitiveStream.GetType(ctx)
+		require.NoError(t, err, "Failed to get type")
+		_, err = composedStream.GetType(ctx)
+		require.NoError(t, err, "Failed to get type")
+		assert.Equal(t, int32(1), metadataRequests.Load())
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		primitiveStream.Refresh()
+
+		_, err := stream.GetType(ctx)
+		require.NoError(t, err, "Failed to get type")
+		assert.Equal(t, int32(2), metadataRequests.Load())
+		// the stream is checked again too
+		assert.Equal(t, 2, node.Requests("GetSchema"))
+	})
+
+	t.Run("ConcurrentUse", func(t *testing.T) {
+		handles := []*contractsapi.Stream{stream, &primitiveStream.Stream, &composedStream.Stream}
+		var wg sync.WaitGroup
+		for i := 0; i < 30; i++ {
+			wg.Add(1)
+			go func(handle *contractsapi.Stream, i int) {
+				defer wg.Done()
+				if i%10 == 0 {
+					handle.Refresh()
+				}
+				streamType, err := handle.GetType(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, types.StreamTypePrimitive, streamType)
+				streamOwner, err := handle.GetStreamOwner(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, owner.Bytes(), streamOwner)
+			}(handles[i%len(handles)], i)
+		}
+		wg.Wait()
+	})
+}
@@ -1,16 +1,121 @@
+// Package logging has the loggers accepted by the SDK. Every client logs through its own *slog.Logger,
+// which discards everything unless one is configured
 package logging
 
 import (
+	"context"
+	"log/slog"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var Logger *zap.Logger
+// Discard returns a logger that drops every record
+func Discard() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// OrDiscard returns the logger, or one that drops every record if it's nil
+func OrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return Discard()
+	}
+	return logger
+}
+
+// NewZapHandler writes slog records to a zap logger
+func NewZapHandler(logger *zap.Logger) slog.Handler {
+	return &zapHandler{logger: logger}
+}
+
+type zapHandler struct {
+	logger *zap.Logger
+}
+
+func (h *zapHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Core().Enabled(zapLevel(level))
+}
+
+func (h *zapHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = append(fields, zapField(attr))
+		return true
+	})
+
+	if entry := h.logger.Check(zapLevel(record.Level), record.Message); entry != nil {
+		if !record.Time.IsZero() {
+			entry.Time = record.Time
+		}
+		entry.Write(fields...)
+	}
+	return nil
+}
+
+func (h *zapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, len(attrs))
+	for i, attr := range attrs {
+		fields[i] = zapField(attr)
+	}
+	return &zapHandler{logger: h.logger.With(fields...)}
+}
+
+func (h *zapHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	// fields added after a namespace are nested in it
+	return &zapHandler{logger: h.logger.With(zap.Namespace(name))}
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+func zapField(attr slog.Attr) zap.Field {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		fields := make([]zap.Field, len(group))
+		for i, groupAttr := range group {
+			fields[i] = zapField(groupAttr)
+		}
+		return zap.Dict(attr.Key, fields...)
+	case slog.KindString:
+		return zap.String(attr.Key, value.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, value.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, value.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, value.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, value.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, value.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, value.Time())
+	}
 
-func init() {
-	var err error
-	Logger, err = zap.NewProduction()
-	if err != nil {
-		panic("Failed to initialize logger: " + err.Error())
+	if err, ok := value.Any().(error); ok {
+		return zap.NamedError(attr.Key, err)
 	}
-	defer Logger.Sync() // flushes buffer, if any
+	return zap.Any(attr.Key, value.Any())
 }
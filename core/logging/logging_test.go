@@ -0,0 +1,43 @@
+package logging_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestLogging checks that SDK log lines reach a zap logger through the slog adapter, and that nothing is logged by default.
+func TestLogging(t *testing.T) {
+	t.Run("ZapHandler", func(t *testing.T) {
+		core, observed := observer.New(zapcore.InfoLevel)
+		logger := slog.New(logging.NewZapHandler(zap.New(core)))
+
+		logger.Debug("filtered out")
+		logger.With("streamId", "st123").WithGroup("tx").Warn("skipping stream", "attempt", 2, "error", errors.New("boom"))
+
+		entries := observed.All()
+		if assert.Equal(t, 1, len(entries)) {
+			assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+			assert.Equal(t, "skipping stream", entries[0].Message)
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "st123", fields["streamId"])
+			group, ok := fields["tx"].(map[string]any)
+			if assert.True(t, ok, "group should be nested") {
+				assert.Equal(t, int64(2), group["attempt"])
+				assert.Equal(t, "boom", group["error"])
+			}
+		}
+	})
+
+	t.Run("DiscardByDefault", func(t *testing.T) {
+		logger := logging.OrDiscard(nil)
+		assert.False(t, logger.Enabled(context.Background(), slog.LevelError))
+	})
+}
@@ -0,0 +1,142 @@
+// Package taxonomygraph renders taxonomy trees as Graphviz DOT and Mermaid diagrams
+package taxonomygraph
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// graphNode is a stream that appears in the diagram. A stream that is a child of
+// many composed streams is rendered once
+type graphNode struct {
+	id    string
+	lines []string
+}
+
+type graphEdge struct {
+	from   string
+	to     string
+	weight string
+}
+
+type graph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// WriteDOT writes the taxonomy tree as a Graphviz DOT digraph
+func WriteDOT(w io.Writer, root *types.TaxonomyNode) error {
+	g, err := buildGraph(root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph taxonomy {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, node := range g.nodes {
+		sb.WriteString(fmt.Sprintf("  %s [label=\"%s\"];\n", node.id, escapeDOT(strings.Join(node.lines, "\n"))))
+	}
+	for _, edge := range g.edges {
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"%s\"];\n", edge.from, edge.to, edge.weight))
+	}
+	sb.WriteString("}\n")
+
+	_, err = io.WriteString(w, sb.String())
+	return errors.WithStack(err)
+}
+
+// WriteMermaid writes the taxonomy tree as a Mermaid flowchart
+func WriteMermaid(w io.Writer, root *types.TaxonomyNode) error {
+	g, err := buildGraph(root)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	for _, node := range g.nodes {
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", node.id, escapeMermaid(strings.Join(node.lines, "<br/>"))))
+	}
+	for _, edge := range g.edges {
+		sb.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", edge.from, edge.weight, edge.to))
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return errors.WithStack(err)
+}
+
+func buildGraph(root *types.TaxonomyNode) (*graph, error) {
+	if root == nil {
+		return nil, errors.New("taxonomy tree is empty")
+	}
+
+	g := &graph{}
+	ids := make(map[string]string)
+
+	var visit func(node *types.TaxonomyNode) string
+	visit = func(node *types.TaxonomyNode) string {
+		key := node.Stream.StreamId.String() + node.Stream.DataProvider.Address()
+		if id, ok := ids[key]; ok {
+			return id
+		}
+
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[key] = id
+		g.nodes = append(g.nodes, graphNode{id: id, lines: nodeLines(node)})
+
+		for _, edge := range node.Children {
+			childId := visit(edge.Child)
+			g.edges = append(g.edges, graphEdge{
+				from:   id,
+				to:     childId,
+				weight: strconv.FormatFloat(edge.Weight, 'f', -1, 64),
+			})
+		}
+		return id
+	}
+	visit(root)
+
+	return g, nil
+}
+
+// nodeLines are the lines of a node label:
+// <display name, if any>
+// <stream id>
+// <type> | read: <visibility> | compose: <visibility>
+func nodeLines(node *types.TaxonomyNode) []string {
+	var lines []string
+	if node.DisplayName != "" {
+		lines = append(lines, node.DisplayName)
+	}
+	lines = append(lines, node.Stream.StreamId.String())
+	lines = append(lines, fmt.Sprintf("%s | read: %s | compose: %s",
+		node.Type,
+		visibilityString(node.ReadVisibility),
+		visibilityString(node.ComposeVisibility),
+	))
+	return lines
+}
+
+func visibilityString(visibility *util.VisibilityEnum) string {
+	if visibility == nil {
+		return "unset"
+	}
+	return visibility.String()
+}
+
+func escapeDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func escapeMermaid(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
@@ -0,0 +1,59 @@
+package taxonomygraph_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/taxonomygraph"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// TestTaxonomyGraph checks the DOT and Mermaid output for a small tree.
+func TestTaxonomyGraph(t *testing.T) {
+	dataProvider := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000001")
+	public := util.PublicVisibility
+	private := util.PrivateVisibility
+
+	leaf := &types.TaxonomyNode{
+		Stream:            types.StreamLocator{StreamId: util.GenerateStreamId("taxonomy-graph-leaf"), DataProvider: dataProvider},
+		Type:              types.StreamTypePrimitive,
+		ReadVisibility:    &public,
+		ComposeVisibility: &private,
+	}
+	root := &types.TaxonomyNode{
+		Stream:            types.StreamLocator{StreamId: util.GenerateStreamId("taxonomy-graph-root"), DataProvider: dataProvider},
+		Type:              types.StreamTypeComposed,
+		ReadVisibility:    &public,
+		ComposeVisibility: &public,
+		DisplayName:       `CPI "headline"`,
+		Children: []types.TaxonomyEdge{
+			{Weight: 0.25, Child: leaf},
+			// same child twice is rendered as a single node
+			{Weight: 0.75, Child: leaf},
+		},
+	}
+
+	t.Run("DOT", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, taxonomygraph.WriteDOT(&buf, root), "Failed to write DOT")
+		out := buf.String()
+		assert.Contains(t, out, "digraph taxonomy {")
+		assert.Contains(t, out, `n0 [label="CPI \"headline\"\n`+root.Stream.StreamId.String()+`\ncomposed | read: public | compose: public"];`)
+		assert.Contains(t, out, `n1 [label="`+leaf.Stream.StreamId.String()+`\nprimitive | read: public | compose: private"];`)
+		assert.Contains(t, out, `n0 -> n1 [label="0.25"];`)
+		assert.Contains(t, out, `n0 -> n1 [label="0.75"];`)
+		assert.NotContains(t, out, "n2")
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, taxonomygraph.WriteMermaid(&buf, root), "Failed to write Mermaid")
+		out := buf.String()
+		assert.Contains(t, out, "graph TD\n")
+		assert.Contains(t, out, `n0["CPI #quot;headline#quot;<br/>`)
+		assert.Contains(t, out, "n0 -->|0.25| n1\n")
+	})
+}
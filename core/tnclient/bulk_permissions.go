@@ -0,0 +1,185 @@
+package tnclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	kwilUtils "github.com/kwilteam/kwil-db/core/utils"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+const defaultBulkConcurrency = 5
+
+// BulkGrantPermission grants a permission on many streams. Streams that already have the grant are skipped
+func (c *Client) BulkGrantPermission(ctx context.Context, params types.BulkPermissionParams) (types.BulkPermissionResults, error) {
+	return c.bulkPermission(ctx, params, c.grantPermission)
+}
+
+// BulkRevokePermission revokes a permission on many streams. Streams without the grant are skipped
+func (c *Client) BulkRevokePermission(ctx context.Context, params types.BulkPermissionParams) (types.BulkPermissionResults, error) {
+	return c.bulkPermission(ctx, params, c.revokePermission)
+}
+
+// permissionOperation sends the transaction for a single stream. A nil hash means the stream was skipped
+type permissionOperation func(ctx context.Context, stream types.IStream, params types.BulkPermissionParams) (transactions.TxHash, error)
+
+func (c *Client) bulkPermission(ctx context.Context, params types.BulkPermissionParams, operation permissionOperation) (types.BulkPermissionResults, error) {
+	// the grantee is read by every worker, and its accessors panic if it wasn't set
+	switch params.Permission {
+	case types.ReadPermission, types.WritePermission:
+		if params.Wallet == (util.EthereumAddress{}) {
+			return nil, errors.Errorf("%s permission needs a wallet", params.Permission)
+		}
+	case types.ComposePermission:
+		locator := params.ComposingStream
+		if locator.StreamId == (util.StreamId{}) || locator.DataProvider == (util.EthereumAddress{}) {
+			return nil, errors.Errorf("%s permission needs a composing stream", params.Permission)
+		}
+	default:
+		return nil, errors.Errorf("unknown permission: %s", params.Permission)
+	}
+
+	streams, err := c.selectStreams(ctx, params.Streams)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	results := make(types.BulkPermissionResults, len(streams))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, locator := range streams {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, locator types.StreamLocator) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i] = types.BulkPermissionResult{Stream: locator}
+
+			stream, err := c.LoadStreamContext(ctx, locator)
+			if err != nil {
+				results[i].Err = errors.WithStack(err)
+				return
+			}
+
+			txHash, err := operation(ctx, stream, params)
+			if err != nil {
+				results[i].Err = errors.WithStack(err)
+				return
+			}
+			if txHash == nil {
+				results[i].Skipped = true
+				return
+			}
+
+			results[i].TxHash = txHash
+			_, results[i].Err = c.WaitForTxSuccess(ctx, txHash, time.Second)
+		}(i, locator)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// permissionMetadata returns the metadata key of the permission, and the ref value stored for its grantee
+func permissionMetadata(params types.BulkPermissionParams) (types.MetadataKey, string) {
+	switch params.Permission {
+	case types.ReadPermission:
+		return types.AllowReadWalletKey, params.Wallet.Address()
+	case types.WritePermission:
+		return types.AllowWriteWalletKey, params.Wallet.Address()
+	default:
+		// streams are stored by DBID
+		locator := params.ComposingStream
+		return types.AllowComposeStreamKey, kwilUtils.GenerateDBID(locator.StreamId.String(), locator.DataProvider.Bytes())
+	}
+}
+
+// grantPermission compares raw ref values, so allowed streams that were destroyed since don't get in the way
+func (c *Client) grantPermission(ctx context.Context, stream types.IStream, params types.BulkPermissionParams) (transactions.TxHash, error) {
+	key, ref := permissionMetadata(params)
+	rows, err := stream.GetMetadata(ctx, types.GetMetadataParams{Key: key, OnlyLatest: true, Ref: ref})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(rows) > 0 {
+		return nil, nil
+	}
+
+	return stream.InsertMetadata(ctx, key, types.NewRefMetadataValue(ref))
+}
+
+// revokePermission disables every row of the grantee, as it keeps the permission while any is enabled
+func (c *Client) revokePermission(ctx context.Context, stream types.IStream, params types.BulkPermissionParams) (transactions.TxHash, error) {
+	key, ref := permissionMetadata(params)
+	rows, err := stream.GetMetadata(ctx, types.GetMetadataParams{Key: key, Ref: ref})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// nothing to revoke
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	rowIds := make([]string, len(rows))
+	for i, row := range rows {
+		rowIds[i] = row.RowId
+	}
+	return stream.BatchDisableMetadata(ctx, rowIds)
+}
+
+// selectStreams resolves the selector into a deduplicated list of streams
+func (c *Client) selectStreams(ctx context.Context, selector types.StreamSelector) ([]types.StreamLocator, error) {
+	var streams []types.StreamLocator
+	seen := make(map[string]bool)
+	var add = func(locator types.StreamLocator) {
+		key := locator.StreamId.String() + locator.DataProvider.Address()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		streams = append(streams, locator)
+	}
+
+	for _, locator := range selector.Streams {
+		add(locator)
+	}
+
+	if selector.Owner != nil {
+		owned, err := c.GetAllInitializedStreams(ctx, types.GetAllStreamsInput{Owner: selector.Owner.Bytes()})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, locator := range owned {
+			add(locator)
+		}
+	}
+
+	if selector.TaxonomyRoot != nil {
+		root, err := c.DescribeTaxonomyTree(ctx, *selector.TaxonomyRoot, types.DescribeTaxonomyTreeParams{})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var walk func(node *types.TaxonomyNode)
+		walk = func(node *types.TaxonomyNode) {
+			add(node.Stream)
+			for _, edge := range node.Children {
+				walk(edge.Child)
+			}
+		}
+		walk(root)
+	}
+
+	return streams, nil
+}
@@ -0,0 +1,36 @@
+package tnclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+func TestBulkPermissionGrantee(t *testing.T) {
+	ctx := context.Background()
+	// the params are checked before any stream is selected, so the client needs no node
+	client := &Client{}
+	streams := types.StreamSelector{Streams: []types.StreamLocator{{
+		StreamId:     util.GenerateStreamId("test-bulk-grantee"),
+		DataProvider: util.Unsafe_NewEthereumAddressFromString("0x1111111111111111111111111111111111111111"),
+	}}}
+
+	for _, params := range []types.BulkPermissionParams{
+		{Permission: types.ReadPermission, Streams: streams},
+		{Permission: types.WritePermission, Streams: streams},
+		{Permission: types.ComposePermission, Streams: streams},
+		{Permission: types.ComposePermission, Streams: streams, ComposingStream: types.StreamLocator{
+			StreamId: util.GenerateStreamId("test-bulk-composing"),
+		}},
+		{Permission: "admin", Streams: streams},
+	} {
+		_, err := client.BulkGrantPermission(ctx, params)
+		assert.Error(t, err, "%s permission without its grantee", params.Permission)
+
+		_, err = client.BulkRevokePermission(ctx, params)
+		assert.Error(t, err, "%s permission without its grantee", params.Permission)
+	}
+}
@@ -2,6 +2,7 @@ package tnclient
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	kwilClientPkg "github.com/kwilteam/kwil-db/core/client"
 	"github.com/kwilteam/kwil-db/core/crypto/auth"
@@ -11,17 +12,32 @@ import (
 	"github.com/pkg/errors"
 	tn_api "github.com/trufnetwork/sdk-go/core/contractsapi"
 	"github.com/trufnetwork/sdk-go/core/logging"
+	"github.com/trufnetwork/sdk-go/core/transport"
 	clientType "github.com/trufnetwork/sdk-go/core/types"
 	"github.com/trufnetwork/sdk-go/core/util"
 	"go.uber.org/zap"
+	"log/slog"
 	"time"
 )
 
 type Client struct {
-	Signer      auth.Signer `validate:"required"`
-	logger      *log.Logger
+	Signer     auth.Signer `validate:"required"`
+	kwilLogger *log.Logger
+	// logger is used for every log line of the SDK, discarding everything by default
+	logger      *slog.Logger
 	kwilClient  *kwilClientPkg.Client `validate:"required"`
 	kwilOptions *kwilClientType.Options
+	// transport is the kwil client wrapped with the configured behaviors, used by every stream
+	transport   kwilClientType.Client
+	retryPolicy *transport.RetryPolicy
+	dryRun      *transport.DryRun
+	telemetry   *transport.TelemetryOptions
+	// endpoints are extra nodes, besides the provider given to NewClient
+	endpoints       []Endpoint
+	endpointOptions transport.MultiEndpointOptions
+	multiEndpoint   *transport.MultiEndpointClient
+	lazyLoading     bool
+	streamCache     *streamCache
 }
 
 var _ clientType.Client = (*Client)(nil)
@@ -29,7 +45,7 @@ var _ clientType.Client = (*Client)(nil)
 type Option func(*Client)
 
 func NewClient(ctx context.Context, provider string, options ...Option) (*Client, error) {
-	c := &Client{}
+	c := &Client{logger: logging.Discard()}
 	c.kwilOptions = kwilClientType.DefaultOptions()
 	kwilClient, err := kwilClientPkg.NewClient(ctx, provider, c.kwilOptions)
 	if err != nil {
@@ -45,9 +61,51 @@ func NewClient(ctx context.Context, provider string, options ...Option) (*Client
 		return nil, errors.WithStack(err)
 	}
 
+	c.transport, err = c.buildTransport(ctx, provider)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	return c, nil
 }
 
+// buildTransport wraps the kwil client with the behaviors set by the options
+func (c *Client) buildTransport(ctx context.Context, provider string) (_ kwilClientType.Client, err error) {
+	// the client is not returned on errors, so the health checks of its endpoints would run forever
+	defer func() {
+		if err != nil && c.multiEndpoint != nil {
+			c.multiEndpoint.Close()
+			c.multiEndpoint = nil
+		}
+	}()
+
+	var t kwilClientType.Client = c.kwilClient
+	if len(c.endpoints) > 0 {
+		multiEndpoint, err := c.buildMultiEndpoint(ctx, provider)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c.multiEndpoint = multiEndpoint
+		t = multiEndpoint
+	}
+	// retries happen once every endpoint failed
+	if c.retryPolicy != nil {
+		t = transport.NewRetryClient(t, *c.retryPolicy, c.kwilClient.Signer.Identity())
+	}
+	// nonces are assigned before retries, so every attempt of a broadcast keeps its nonce
+	t = transport.NewNonceClient(t, c.kwilClient.Signer.Identity())
+	if c.telemetry != nil {
+		telemetryClient, err := transport.NewTelemetryClient(t, *c.telemetry)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		t = telemetryClient
+	}
+	// outermost, so dry runs don't take local nonces
+	t = transport.NewDryRunClient(t, c.kwilClient, c.kwilClient.Signer.Identity(), c.dryRun)
+	return t, nil
+}
+
 func (c *Client) Validate() error {
 	validate := validator.New()
 	return validate.Struct(c)
@@ -60,10 +118,47 @@ func WithSigner(signer auth.Signer) Option {
 	}
 }
 
+// WithLogger sets the logger of the kwil client, and of the SDK
 func WithLogger(logger log.Logger) Option {
 	return func(c *Client) {
-		c.logger = &logger
+		c.kwilLogger = &logger
 		c.kwilOptions.Logger = logger
+		c.logger = slog.New(logging.NewZapHandler(logger.L))
+	}
+}
+
+// WithZapLogger sets a zap logger for the kwil client and the SDK
+func WithZapLogger(logger *zap.Logger) Option {
+	return WithLogger(log.Logger{L: logger})
+}
+
+// WithSlogHandler sets the handler of the SDK log lines, i.e. the one of a service already using slog
+func WithSlogHandler(handler slog.Handler) Option {
+	return func(c *Client) {
+		c.logger = slog.New(handler)
+	}
+}
+
+// WithRetryPolicy retries failed requests caused by transient node errors.
+// See transport.DefaultRetryPolicy for the defaults of zero values
+func WithRetryPolicy(policy transport.RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// WithDryRun makes every write of the client a dry run: checks run as usual, but transactions are
+// collected by dryRun instead of broadcast. Use transport.WithDryRun for a single call
+func WithDryRun(dryRun *transport.DryRun) Option {
+	return func(c *Client) {
+		c.dryRun = dryRun
+	}
+}
+
+// WithTelemetry creates OpenTelemetry spans and metrics for every request of the client and its streams
+func WithTelemetry(options transport.TelemetryOptions) Option {
+	return func(c *Client) {
+		c.telemetry = &options
 	}
 }
 
@@ -72,7 +167,21 @@ func (c *Client) GetSigner() auth.Signer {
 }
 
 func (c *Client) WaitForTx(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
-	return c.kwilClient.WaitTx(ctx, txHash, interval)
+	return c.transport.WaitTx(ctx, txHash, interval)
+}
+
+// WaitForTxSuccess waits for the transaction to be mined, and returns a *types.TxFailedError if its result is not OK
+func (c *Client) WaitForTxSuccess(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	txRes, err := c.WaitForTx(ctx, txHash, interval)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if transactions.TxCode(txRes.TxResult.Code) != transactions.CodeOk {
+		return txRes, clientType.NewTxFailedError(txHash, txRes.TxResult)
+	}
+
+	return txRes, nil
 }
 
 func (c *Client) GetKwilClient() *kwilClientPkg.Client {
@@ -83,7 +192,7 @@ func (c *Client) DeployStream(ctx context.Context, streamId util.StreamId, strea
 	return tn_api.DeployStream(ctx, tn_api.DeployStreamInput{
 		StreamId:   streamId,
 		StreamType: streamType,
-		KwilClient: c.kwilClient,
+		KwilClient: c.transport,
 		Deployer:   c.kwilClient.Signer.Identity(),
 	})
 }
@@ -91,37 +200,84 @@ func (c *Client) DeployStream(ctx context.Context, streamId util.StreamId, strea
 func (c *Client) DestroyStream(ctx context.Context, streamId util.StreamId) (transactions.TxHash, error) {
 	out, err := tn_api.DestroyStream(ctx, tn_api.DestroyStreamInput{
 		StreamId:   streamId,
-		KwilClient: c.kwilClient,
+		KwilClient: c.transport,
 	})
 	if err != nil {
 		return transactions.TxHash{}, errors.WithStack(err)
 	}
+	c.streamCache.remove(c.OwnStreamLocator(streamId))
 
 	return out.TxHash, nil
 }
 
 func (c *Client) LoadStream(streamLocator clientType.StreamLocator) (clientType.IStream, error) {
-	return tn_api.LoadStream(tn_api.NewStreamOptions{
-		Client:   c.kwilClient,
-		StreamId: streamLocator.StreamId,
-		Deployer: streamLocator.DataProvider.Bytes(),
-	})
+	return c.LoadStreamContext(context.Background(), streamLocator)
+}
+
+// LoadStreamContext is LoadStream, checking if the stream is deployed with the given context
+func (c *Client) LoadStreamContext(ctx context.Context, streamLocator clientType.StreamLocator) (clientType.IStream, error) {
+	stream, err := c.loadStream(ctx, streamLocator)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return stream, nil
 }
 
 func (c *Client) LoadPrimitiveStream(streamLocator clientType.StreamLocator) (clientType.IPrimitiveStream, error) {
-	return tn_api.LoadPrimitiveStream(tn_api.NewStreamOptions{
-		Client:   c.kwilClient,
-		StreamId: streamLocator.StreamId,
-		Deployer: streamLocator.DataProvider.Bytes(),
-	})
+	return c.LoadPrimitiveStreamContext(context.Background(), streamLocator)
+}
+
+// LoadPrimitiveStreamContext is LoadPrimitiveStream, checking if the stream is deployed with the given context
+func (c *Client) LoadPrimitiveStreamContext(ctx context.Context, streamLocator clientType.StreamLocator) (clientType.IPrimitiveStream, error) {
+	stream, err := c.loadStream(ctx, streamLocator)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tn_api.PrimitiveStreamFromStream(*stream)
 }
 
 func (c *Client) LoadComposedStream(streamLocator clientType.StreamLocator) (clientType.IComposedStream, error) {
-	return tn_api.LoadComposedStream(tn_api.NewStreamOptions{
-		Client:   c.kwilClient,
+	return c.LoadComposedStreamContext(context.Background(), streamLocator)
+}
+
+// LoadComposedStreamContext is LoadComposedStream, checking if the stream is deployed with the given context
+func (c *Client) LoadComposedStreamContext(ctx context.Context, streamLocator clientType.StreamLocator) (clientType.IComposedStream, error) {
+	stream, err := c.loadStream(ctx, streamLocator)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tn_api.ComposedStreamFromStream(*stream)
+}
+
+// loadStream returns the cached stream of the locator, or loads it as configured
+func (c *Client) loadStream(ctx context.Context, streamLocator clientType.StreamLocator) (*tn_api.Stream, error) {
+	if stream, ok := c.streamCache.get(streamLocator); ok {
+		return stream, nil
+	}
+
+	stream, err := tn_api.LoadStreamContext(ctx, c.streamOptions(streamLocator, c.lazyLoading))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return c.streamCache.put(stream), nil
+}
+
+// checkStreamDeployed asks the node if the stream is deployed, regardless of lazy loading and the cache
+func (c *Client) checkStreamDeployed(ctx context.Context, streamLocator clientType.StreamLocator) error {
+	_, err := tn_api.LoadStreamContext(ctx, c.streamOptions(streamLocator, false))
+	return errors.WithStack(err)
+}
+
+func (c *Client) streamOptions(streamLocator clientType.StreamLocator, lazy bool) tn_api.NewStreamOptions {
+	return tn_api.NewStreamOptions{
+		Client:   c.transport,
 		StreamId: streamLocator.StreamId,
 		Deployer: streamLocator.DataProvider.Bytes(),
-	})
+		Signer:   c.kwilClient.Signer.Identity(),
+		Logger:   c.logger,
+		Lazy:     lazy,
+	}
 }
 
 func (c *Client) OwnStreamLocator(streamId util.StreamId) clientType.StreamLocator {
@@ -135,7 +291,7 @@ func (c *Client) Address() util.EthereumAddress {
 	address, err := util.NewEthereumAddressFromBytes(c.kwilClient.Signer.Identity())
 	if err != nil {
 		// should never happen
-		logging.Logger.Panic("failed to get address from signer", zap.Error(err))
+		panic(fmt.Sprintf("failed to get address from signer: %v", err))
 	}
 	return address
 }
@@ -2,72 +2,101 @@ package tnclient
 
 import (
 	"context"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
 	"github.com/pkg/errors"
-	"github.com/trufnetwork/sdk-go/core/logging"
 	"github.com/trufnetwork/sdk-go/core/types"
 	"github.com/trufnetwork/sdk-go/core/util"
-	"go.uber.org/zap"
-	"time"
 )
 
-// DeployComposedStreamsWithTaxonomy deploys a composed stream with taxonomy
-func (c *Client) DeployComposedStreamWithTaxonomy(ctx context.Context, streamId util.StreamId, taxonomy types.Taxonomy) error {
+// DeployComposedStreamsWithTaxonomy deploys a composed stream with taxonomy.
+// Options are the ones of SetTaxonomy, i.e. types.WithComposeGrants(). Compose grants are mined before the taxonomy is set
+func (c *Client) DeployComposedStreamWithTaxonomy(ctx context.Context, streamId util.StreamId, taxonomy types.Taxonomy, opts ...types.SetTaxonomyOption) error {
 	// check if the stream on taxonomies is already deployed
 	for _, item := range taxonomy.TaxonomyItems {
-		_, err := c.LoadStream(item.ChildStream)
+		err := c.checkStreamDeployed(ctx, item.ChildStream)
 		if err != nil {
 			return errors.WithStack(err)
 		}
 	}
 
 	// check if the stream is already deployed
-	_, err := c.LoadStream(c.OwnStreamLocator(streamId))
+	err := c.checkStreamDeployed(ctx, c.OwnStreamLocator(streamId))
 	if err == nil {
 		return errors.New("stream already deployed")
 	}
 
-	// create the stream
-	txHashCreate, err := c.DeployStream(ctx, streamId, types.StreamTypeComposed)
-	if err != nil {
-		return errors.WithStack(err)
-	}
-
-	_, err = c.WaitForTx(ctx, txHashCreate, time.Second*10)
-	if err != nil {
-		return errors.WithStack(err)
+	var options types.SetTaxonomyOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
-	logging.Logger.Info("Deployed stream, with txHash", zap.String("streamId", streamId.String()), zap.String("txHash", txHashCreate.Hex()))
 
-	// load the stream
 	streamLocator := c.OwnStreamLocator(streamId)
-	stream, err := c.LoadComposedStream(streamLocator)
-	if err != nil {
+	logSuccess := WithTxCallback(func(result TrackedTxResult) {
+		if result.Err == nil {
+			c.logger.Info(result.Label, "streamId", streamId.String(), "txHash", result.TxHash.Hex())
+		}
+	})
+
+	// each step needs the previous one to be mined
+	tracker := c.NewTxTracker(logSuccess)
+	tracker.Submit("deploy stream", func(ctx context.Context) (transactions.TxHash, error) {
+		return c.DeployStream(ctx, streamId, types.StreamTypeComposed)
+	})
+	tracker.Submit("initialize stream", func(ctx context.Context) (transactions.TxHash, error) {
+		stream, err := c.LoadComposedStreamContext(ctx, streamLocator)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return stream.InitializeStream(ctx)
+	}, "deploy stream")
+	if err := waitTrackedTxs(ctx, tracker); err != nil {
 		return errors.WithStack(err)
 	}
 
-	// initialize the stream
-	txHashInit, err := stream.InitializeStream(ctx)
+	stream, err := c.LoadComposedStreamContext(ctx, streamLocator)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	_, err = c.WaitForTx(ctx, txHashInit, time.Second*10)
-	if err != nil {
-		return errors.WithStack(err)
+	// grants need the stream to be initialized, and must be mined before the taxonomy is used
+	tracker = c.NewTxTracker(logSuccess)
+	var grantLabels []string
+	if options.GrantCompose {
+		grantResults, err := stream.GrantComposeToPrivateChildren(ctx, taxonomy)
+		if options.GrantResults != nil {
+			*options.GrantResults = grantResults
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, result := range grantResults {
+			if result.TxHash == nil {
+				continue
+			}
+			label := "grant compose on " + result.Child.StreamId.String()
+			tracker.Track(label, result.TxHash)
+			grantLabels = append(grantLabels, label)
+		}
 	}
-	logging.Logger.Info("Initialized stream", zap.String("streamId", streamId.String()), zap.String("txHash", txHashInit.Hex()))
+	tracker.Submit("set taxonomy", func(ctx context.Context) (transactions.TxHash, error) {
+		return stream.SetTaxonomy(ctx, taxonomy)
+	}, grantLabels...)
 
-	// set the taxonomy
-	txHashSet, err := stream.SetTaxonomy(ctx, taxonomy)
+	return errors.WithStack(waitTrackedTxs(ctx, tracker))
+}
+
+// waitTrackedTxs waits for the tracker, returning the first failure. The next steps are skipped because of it
+func waitTrackedTxs(ctx context.Context, tracker *TxTracker) error {
+	outcome, err := tracker.Wait(ctx)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	_, err = c.WaitForTx(ctx, txHashSet, time.Second*10)
-	if err != nil {
-		return errors.WithStack(err)
+	for _, result := range outcome.Results {
+		if result.Err != nil && !result.Skipped {
+			return errors.Wrap(result.Err, result.Label)
+		}
 	}
-	logging.Logger.Info("Set taxonomy for stream", zap.String("streamId", streamId.String()), zap.String("txHash", txHashSet.Hex()))
 
 	return nil
 }
@@ -0,0 +1,92 @@
+package tnclient
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// DescribeTaxonomyTree walks the taxonomy of a stream down to its leaves
+func (c *Client) DescribeTaxonomyTree(ctx context.Context, root types.StreamLocator, params types.DescribeTaxonomyTreeParams) (*types.TaxonomyNode, error) {
+	return c.describeTaxonomyNode(ctx, root, params, 0, map[string]bool{})
+}
+
+// describeTaxonomyNode describes a stream and, if it's composed, its children recursively.
+// path holds the streams from the root to this one, so cycles can be detected
+func (c *Client) describeTaxonomyNode(
+	ctx context.Context,
+	locator types.StreamLocator,
+	params types.DescribeTaxonomyTreeParams,
+	depth int,
+	path map[string]bool,
+) (*types.TaxonomyNode, error) {
+	key := locator.StreamId.String() + locator.DataProvider.Address()
+	if path[key] {
+		return nil, errors.New(fmt.Sprintf("taxonomy cycle detected at stream %s", locator.StreamId.String()))
+	}
+	path[key] = true
+	defer delete(path, key)
+
+	stream, err := c.loadStream(ctx, locator)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load stream %s", locator.StreamId.String())
+	}
+
+	streamType, err := stream.GetType(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get type of stream %s", locator.StreamId.String())
+	}
+
+	readVisibility, err := stream.GetReadVisibility(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	composeVisibility, err := stream.GetComposeVisibility(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	node := &types.TaxonomyNode{
+		Stream:            locator,
+		Type:              streamType,
+		ReadVisibility:    readVisibility,
+		ComposeVisibility: composeVisibility,
+	}
+
+	if params.IncludeDisplayNames {
+		node.DisplayName, err = stream.GetDisplayName(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if streamType != types.StreamTypeComposed || (params.MaxDepth > 0 && depth >= params.MaxDepth) {
+		return node, nil
+	}
+
+	// the stream is already loaded, so converting it fetches nothing again
+	composedStream, err := stream.ToComposedStream()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	taxonomy, err := composedStream.DescribeTaxonomies(ctx, types.DescribeTaxonomiesParams{LatestVersion: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describe taxonomies of stream %s", locator.StreamId.String())
+	}
+
+	for _, item := range taxonomy.TaxonomyItems {
+		child, err := c.describeTaxonomyNode(ctx, item.ChildStream, params, depth+1, path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Children = append(node.Children, types.TaxonomyEdge{
+			Weight: item.Weight,
+			Child:  child,
+		})
+	}
+
+	return node, nil
+}
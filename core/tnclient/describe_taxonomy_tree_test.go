@@ -0,0 +1,59 @@
+package tnclient
+
+import (
+	"context"
+	"testing"
+
+	kwilClientPkg "github.com/kwilteam/kwil-db/core/client"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestDescribeTaxonomyTreeLoads checks that each stream of the tree is loaded once
+func TestDescribeTaxonomyTreeLoads(t *testing.T) {
+	ctx := context.Background()
+	pk, err := crypto.Secp256k1PrivateKeyFromHex("0000000000000000000000000000000000000000000000000000000000000001")
+	require.NoError(t, err)
+	owner := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000123")
+
+	root := types.StreamLocator{StreamId: util.GenerateStreamId("test-tree-root"), DataProvider: owner}
+	child := types.StreamLocator{StreamId: util.GenerateStreamId("test-tree-child"), DataProvider: owner}
+	rootDBID := utils.GenerateDBID(root.StreamId.String(), owner.Bytes())
+
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			if procedure == "describe_taxonomies" {
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{
+					"child_stream_id":     child.StreamId.String(),
+					"child_data_provider": owner.Address(),
+					"weight":              "1",
+				}}), nil
+			}
+			if inputs[0] == types.TypeKey.String() {
+				streamType := "primitive"
+				if dbid == rootDBID {
+					streamType = "composed"
+				}
+				return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value_s": streamType}}), nil
+			}
+			return kwilClientType.NewRecordsFromMaps(nil), nil
+		},
+	}
+	client := &Client{
+		kwilClient: &kwilClientPkg.Client{Signer: &auth.EthPersonalSigner{Key: *pk}},
+		transport:  node,
+	}
+
+	tree, err := client.DescribeTaxonomyTree(ctx, root, types.DescribeTaxonomyTreeParams{})
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, child, tree.Children[0].Child.Stream)
+	assert.Equal(t, 2, node.Requests("GetSchema"), "one schema request per stream")
+}
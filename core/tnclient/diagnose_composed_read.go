@@ -0,0 +1,73 @@
+package tnclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// DiagnoseComposedRead finds the streams that block a wallet from reading a stream.
+// Every stream in the latest taxonomy must be readable by the wallet and allow its parent to compose it
+func (c *Client) DiagnoseComposedRead(ctx context.Context, locator types.StreamLocator, wallet util.EthereumAddress) (types.ReadDiagnosis, error) {
+	root, err := c.DescribeTaxonomyTree(ctx, locator, types.DescribeTaxonomyTreeParams{})
+	if err != nil {
+		return types.ReadDiagnosis{}, errors.WithStack(err)
+	}
+
+	var diagnosis types.ReadDiagnosis
+	// a stream can be child of many parents, but readability only needs to be checked once
+	checkedRead := make(map[string]bool)
+
+	var check func(node *types.TaxonomyNode, parent *types.StreamLocator) error
+	check = func(node *types.TaxonomyNode, parent *types.StreamLocator) error {
+		stream, err := c.LoadStreamContext(ctx, node.Stream)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		key := node.Stream.StreamId.String() + node.Stream.DataProvider.Address()
+		if !checkedRead[key] {
+			checkedRead[key] = true
+			canRead, err := stream.CanRead(ctx, wallet)
+			if err != nil {
+				return errors.Wrapf(err, "check read permission of stream %s", node.Stream.StreamId.String())
+			}
+			if !canRead {
+				diagnosis.Blockers = append(diagnosis.Blockers, types.ReadBlocker{
+					Stream:            node.Stream,
+					Parent:            parent,
+					MissingPermission: types.ReadPermission,
+				})
+			}
+		}
+
+		if parent != nil {
+			canCompose, err := stream.CanCompose(ctx, *parent)
+			if err != nil {
+				return errors.Wrapf(err, "check compose permission of stream %s", node.Stream.StreamId.String())
+			}
+			if !canCompose {
+				diagnosis.Blockers = append(diagnosis.Blockers, types.ReadBlocker{
+					Stream:            node.Stream,
+					Parent:            parent,
+					MissingPermission: types.ComposePermission,
+				})
+			}
+		}
+
+		for _, edge := range node.Children {
+			if err := check(edge.Child, &node.Stream); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check(root, nil); err != nil {
+		return types.ReadDiagnosis{}, err
+	}
+
+	return diagnosis, nil
+}
@@ -0,0 +1,69 @@
+package tnclient
+
+import (
+	"context"
+	kwilClientPkg "github.com/kwilteam/kwil-db/core/client"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/transport"
+)
+
+// Endpoint is an extra node used by the client, besides the provider given to NewClient
+type Endpoint struct {
+	Provider string
+	// ReadOnly endpoints are only used for view calls
+	ReadOnly bool
+	// ReadPreference orders the endpoints for view calls, lower first. The provider given to NewClient has 0
+	ReadPreference int
+	// WritePreference orders the endpoints for broadcasts, lower first. The provider given to NewClient has 0
+	WritePreference int
+}
+
+// WithEndpoints adds nodes to fail over to. They are health checked in the background until Close is called.
+// See transport.DefaultMultiEndpointOptions for the defaults of zero values
+func WithEndpoints(options transport.MultiEndpointOptions, endpoints ...Endpoint) Option {
+	return func(c *Client) {
+		c.endpointOptions = options
+		c.endpoints = append(c.endpoints, endpoints...)
+	}
+}
+
+// buildMultiEndpoint uses the provider given to NewClient as the first endpoint, already connected
+func (c *Client) buildMultiEndpoint(ctx context.Context, provider string) (*transport.MultiEndpointClient, error) {
+	endpoints := []transport.Endpoint{{
+		Name: provider,
+		Connect: func(ctx context.Context) (kwilClientType.Client, error) {
+			return c.kwilClient, nil
+		},
+	}}
+
+	for _, endpoint := range c.endpoints {
+		endpointProvider := endpoint.Provider
+		endpoints = append(endpoints, transport.Endpoint{
+			Name: endpointProvider,
+			Connect: func(ctx context.Context) (kwilClientType.Client, error) {
+				// the chain id is set, so nodes of another chain are refused
+				options := *c.kwilOptions
+				options.Signer = c.kwilClient.Signer
+				options.ChainID = c.kwilClient.ChainID()
+				kwilClient, err := kwilClientPkg.NewClient(ctx, endpointProvider, &options)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				return kwilClient, nil
+			},
+			ReadOnly:        endpoint.ReadOnly,
+			ReadPreference:  endpoint.ReadPreference,
+			WritePreference: endpoint.WritePreference,
+		})
+	}
+
+	return transport.NewMultiEndpointClient(ctx, endpoints, c.endpointOptions)
+}
+
+// Close stops the background work of the client, such as endpoint health checks
+func (c *Client) Close() {
+	if c.multiEndpoint != nil {
+		c.multiEndpoint.Close()
+	}
+}
@@ -4,15 +4,13 @@ import (
 	"context"
 	kwiltypes "github.com/kwilteam/kwil-db/core/types"
 	"github.com/pkg/errors"
-	"github.com/trufnetwork/sdk-go/core/logging"
 	tntypes "github.com/trufnetwork/sdk-go/core/types"
 	"github.com/trufnetwork/sdk-go/core/util"
-	"go.uber.org/zap"
 )
 
 // GetAllStreams returns all streams from the TN network
 func (c *Client) GetAllStreams(ctx context.Context, input tntypes.GetAllStreamsInput) ([]tntypes.StreamLocator, error) {
-	kwilClient := c.GetKwilClient()
+	kwilClient := c.transport
 
 	// get all deployed contracts
 	contracts, err := kwilClient.ListDatabases(ctx, input.Owner)
@@ -55,7 +53,7 @@ func (c *Client) GetAllStreams(ctx context.Context, input tntypes.GetAllStreamsI
 }
 
 func (c *Client) GetAllInitializedStreams(ctx context.Context, input tntypes.GetAllStreamsInput) ([]tntypes.StreamLocator, error) {
-	kwilClient := c.GetKwilClient()
+	kwilClient := c.transport
 
 	// get all deployed contracts
 	contracts, err := kwilClient.ListDatabases(ctx, input.Owner)
@@ -94,10 +92,10 @@ func (c *Client) GetAllInitializedStreams(ctx context.Context, input tntypes.Get
 			}
 
 			// check if the stream is initialized by trying to load it and get its type
-			deployedStream, err := c.LoadStream(streamLocator)
+			deployedStream, err := c.LoadStreamContext(ctx, streamLocator)
 			if err != nil {
 				// in case of error, we just continue to the next stream
-				logging.Logger.Warn("skipping stream due to error on load", zap.String("streamId", streamId.String()), zap.Error(err))
+				c.logger.Warn("skipping stream due to error on load", "streamId", streamId.String(), "error", err)
 				continue
 			}
 
@@ -105,13 +103,13 @@ func (c *Client) GetAllInitializedStreams(ctx context.Context, input tntypes.Get
 			values, err := deployedStream.GetType(ctx)
 			if err != nil {
 				// in case of error, we just continue to the next stream, it means the stream is not initialized
-				logging.Logger.Warn("skipping stream due to error on get type", zap.String("streamId", streamId.String()), zap.Error(err))
+				c.logger.Warn("skipping stream due to error on get type", "streamId", streamId.String(), "error", err)
 				continue
 			}
 
 			if len(values) == 0 {
 				// type can't ever be disabled
-				logging.Logger.Warn("no type found on stream, check if the stream is initialized, skipping", zap.String("streamId", streamId.String()))
+				c.logger.Warn("no type found on stream, check if the stream is initialized, skipping", "streamId", streamId.String())
 				continue
 			}
 
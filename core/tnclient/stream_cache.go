@@ -0,0 +1,67 @@
+package tnclient
+
+import (
+	kwilUtils "github.com/kwilteam/kwil-db/core/utils"
+	tn_api "github.com/trufnetwork/sdk-go/core/contractsapi"
+	clientType "github.com/trufnetwork/sdk-go/core/types"
+	"sync"
+)
+
+// WithLazyLoading makes the client load streams without any request. Whether a stream is deployed is
+// checked on its first request instead, failing with contractsapi.ErrorStreamNotFound if it isn't
+func WithLazyLoading() Option {
+	return func(c *Client) {
+		c.lazyLoading = true
+	}
+}
+
+// WithStreamCache keeps the streams loaded by the client, so loading the same locator again
+// returns the same handle, without any request. Streams destroyed by the client are dropped from it
+func WithStreamCache() Option {
+	return func(c *Client) {
+		c.streamCache = &streamCache{streams: make(map[string]*tn_api.Stream)}
+	}
+}
+
+// streamCache holds the loaded streams by their DBID. A nil cache keeps nothing
+type streamCache struct {
+	mu      sync.Mutex
+	streams map[string]*tn_api.Stream
+}
+
+func streamDBID(locator clientType.StreamLocator) string {
+	return kwilUtils.GenerateDBID(locator.StreamId.String(), locator.DataProvider.Bytes())
+}
+
+func (sc *streamCache) get(locator clientType.StreamLocator) (*tn_api.Stream, bool) {
+	if sc == nil {
+		return nil, false
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	stream, ok := sc.streams[streamDBID(locator)]
+	return stream, ok
+}
+
+// put stores the stream, unless another one was stored for the same locator meanwhile, and returns the stored one
+func (sc *streamCache) put(stream *tn_api.Stream) *tn_api.Stream {
+	if sc == nil {
+		return stream
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if cached, ok := sc.streams[stream.DBID]; ok {
+		return cached
+	}
+	sc.streams[stream.DBID] = stream
+	return stream
+}
+
+func (sc *streamCache) remove(locator clientType.StreamLocator) {
+	if sc == nil {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.streams, streamDBID(locator))
+}
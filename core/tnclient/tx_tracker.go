@@ -0,0 +1,244 @@
+package tnclient
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrorTxDependencyFailed = errors.New("transaction dependency failed")
+	ErrorTxTrackerWaited    = errors.New("transaction tracker was already waited for")
+)
+
+// TxWaiter waits for a transaction, returning an error if it failed. Client.WaitForTxSuccess is one
+type TxWaiter func(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error)
+
+// TxSubmitter sends a transaction once its dependencies are mined successfully
+type TxSubmitter func(ctx context.Context) (transactions.TxHash, error)
+
+// TrackedTxResult is the outcome of a tracked transaction
+type TrackedTxResult struct {
+	Label  string
+	TxHash transactions.TxHash
+	// Response is nil if the transaction wasn't sent or mined
+	Response *transactions.TcTxQueryResponse
+	// Skipped is true if the transaction wasn't sent because a dependency failed
+	Skipped bool
+	// Err is nil if the transaction was mined with an OK result
+	Err error
+}
+
+// TxTrackerOutcome has the results in the order the transactions were added
+type TxTrackerOutcome struct {
+	Results []TrackedTxResult
+}
+
+// Succeeded returns true if every transaction was mined with an OK result
+func (o TxTrackerOutcome) Succeeded() bool {
+	return o.Err() == nil
+}
+
+// Err joins the errors of every failed transaction
+func (o TxTrackerOutcome) Err() error {
+	var errs []error
+	for _, result := range o.Results {
+		if result.Err != nil {
+			errs = append(errs, errors.Wrap(result.Err, result.Label))
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// Get returns the result of a label
+func (o TxTrackerOutcome) Get(label string) (TrackedTxResult, bool) {
+	for _, result := range o.Results {
+		if result.Label == label {
+			return result, true
+		}
+	}
+	return TrackedTxResult{}, false
+}
+
+type TxTrackerOption func(*TxTracker)
+
+// WithTrackerInterval sets how often transactions are polled. Defaults to 1 second
+func WithTrackerInterval(interval time.Duration) TxTrackerOption {
+	return func(t *TxTracker) {
+		t.interval = interval
+	}
+}
+
+// WithTxCallback is called once for every result, as soon as it's known
+func WithTxCallback(callback func(result TrackedTxResult)) TxTrackerOption {
+	return func(t *TxTracker) {
+		t.callback = callback
+	}
+}
+
+type trackedTx struct {
+	label     string
+	txHash    transactions.TxHash
+	submit    TxSubmitter
+	dependsOn []string
+	result    TrackedTxResult
+	done      chan struct{}
+}
+
+// TxTracker waits for many transactions concurrently. Transactions can be added already sent,
+// or as submitters that are only sent after the transactions they depend on are mined successfully
+type TxTracker struct {
+	wait     TxWaiter
+	interval time.Duration
+	callback func(result TrackedTxResult)
+	txs      []*trackedTx
+	byLabel  map[string]*trackedTx
+	// waited is set by the first Wait, as transactions can only be sent once
+	waited atomic.Bool
+}
+
+// NewTxTracker creates a tracker waiting with Client.WaitForTxSuccess
+func (c *Client) NewTxTracker(opts ...TxTrackerOption) *TxTracker {
+	return NewTxTracker(c.WaitForTxSuccess, opts...)
+}
+
+// NewTxTracker creates a tracker with a custom waiter
+func NewTxTracker(wait TxWaiter, opts ...TxTrackerOption) *TxTracker {
+	t := &TxTracker{
+		wait:     wait,
+		interval: time.Second,
+		byLabel:  make(map[string]*trackedTx),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Track adds a transaction that was already sent
+func (t *TxTracker) Track(label string, txHash transactions.TxHash) {
+	t.add(&trackedTx{label: label, txHash: txHash})
+}
+
+// Submit adds a transaction to be sent once the transactions with the given labels are mined successfully
+func (t *TxTracker) Submit(label string, submit TxSubmitter, dependsOn ...string) {
+	t.add(&trackedTx{label: label, submit: submit, dependsOn: dependsOn})
+}
+
+func (t *TxTracker) add(tx *trackedTx) {
+	tx.done = make(chan struct{})
+	tx.result.Label = tx.label
+	tx.result.TxHash = tx.txHash
+	t.txs = append(t.txs, tx)
+	t.byLabel[tx.label] = tx
+}
+
+// Wait sends the pending transactions and waits for all of them. It can only be called once,
+// later calls fail with ErrorTxTrackerWaited. Failures of single transactions are in the outcome,
+// the error is only for invalid dependencies
+func (t *TxTracker) Wait(ctx context.Context) (TxTrackerOutcome, error) {
+	if !t.waited.CompareAndSwap(false, true) {
+		return TxTrackerOutcome{}, ErrorTxTrackerWaited
+	}
+
+	if err := t.checkDependencies(); err != nil {
+		return TxTrackerOutcome{}, errors.WithStack(err)
+	}
+
+	var wg sync.WaitGroup
+	var callbackMutex sync.Mutex
+	for _, tx := range t.txs {
+		wg.Add(1)
+		go func(tx *trackedTx) {
+			defer wg.Done()
+			defer close(tx.done)
+
+			t.run(ctx, tx)
+
+			if t.callback != nil {
+				callbackMutex.Lock()
+				t.callback(tx.result)
+				callbackMutex.Unlock()
+			}
+		}(tx)
+	}
+	wg.Wait()
+
+	outcome := TxTrackerOutcome{Results: make([]TrackedTxResult, len(t.txs))}
+	for i, tx := range t.txs {
+		outcome.Results[i] = tx.result
+	}
+	return outcome, nil
+}
+
+func (t *TxTracker) run(ctx context.Context, tx *trackedTx) {
+	for _, label := range tx.dependsOn {
+		dependency := t.byLabel[label]
+		select {
+		case <-dependency.done:
+		case <-ctx.Done():
+			tx.result.Err = errors.WithStack(ctx.Err())
+			return
+		}
+		if dependency.result.Err != nil {
+			tx.result.Skipped = true
+			tx.result.Err = errors.Wrap(ErrorTxDependencyFailed, label)
+			return
+		}
+	}
+
+	if tx.submit != nil {
+		txHash, err := tx.submit(ctx)
+		if err != nil {
+			tx.result.Err = errors.WithStack(err)
+			return
+		}
+		tx.txHash = txHash
+		tx.result.TxHash = txHash
+	}
+
+	tx.result.Response, tx.result.Err = t.wait(ctx, tx.txHash, t.interval)
+}
+
+// checkDependencies fails on duplicated or unknown labels, and on cycles
+func (t *TxTracker) checkDependencies() error {
+	if len(t.byLabel) != len(t.txs) {
+		return errors.New("transaction labels must be unique")
+	}
+
+	// 0: not visited, 1: visiting, 2: visited
+	state := make(map[string]int)
+	var visit func(label string) error
+	visit = func(label string) error {
+		tx, ok := t.byLabel[label]
+		if !ok {
+			return errors.New(fmt.Sprintf("unknown transaction dependency: %s", label))
+		}
+		switch state[label] {
+		case 1:
+			return errors.New(fmt.Sprintf("transaction dependency cycle at: %s", label))
+		case 2:
+			return nil
+		}
+		state[label] = 1
+		for _, dependency := range tx.dependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[label] = 2
+		return nil
+	}
+
+	for _, tx := range t.txs {
+		if err := visit(tx.label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
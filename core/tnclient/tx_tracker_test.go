@@ -0,0 +1,136 @@
+package tnclient_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// fakeTxWaiter mines transactions right away. Transactions starting with 0xff fail
+type fakeTxWaiter struct {
+	mu     sync.Mutex
+	waited []transactions.TxHash
+}
+
+func (f *fakeTxWaiter) wait(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	f.mu.Lock()
+	f.waited = append(f.waited, txHash)
+	f.mu.Unlock()
+
+	result := transactions.TransactionResult{Code: transactions.CodeOk.Uint32()}
+	if txHash[0] == 0xff {
+		result = transactions.TransactionResult{Code: transactions.CodeUnknownError.Uint32(), Log: "ERROR: boom"}
+		return &transactions.TcTxQueryResponse{TxResult: result}, types.NewTxFailedError(txHash, result)
+	}
+	return &transactions.TcTxQueryResponse{TxResult: result}, nil
+}
+
+// TestTxTracker checks the aggregate outcome, the callbacks and the dependencies of tracked transactions.
+func TestTxTracker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TrackSentTransactions", func(t *testing.T) {
+		waiter := &fakeTxWaiter{}
+		var labels []string
+		tracker := tnclient.NewTxTracker(waiter.wait, tnclient.WithTxCallback(func(result tnclient.TrackedTxResult) {
+			labels = append(labels, result.Label)
+		}))
+		tracker.Track("first", transactions.TxHash{1})
+		tracker.Track("second", transactions.TxHash{0xff})
+
+		outcome, err := tracker.Wait(ctx)
+		require.NoError(t, err, "Wait should not fail")
+		assert.ElementsMatch(t, []string{"first", "second"}, labels)
+		assert.False(t, outcome.Succeeded())
+
+		first, _ := outcome.Get("first")
+		assert.NoError(t, first.Err)
+		second, _ := outcome.Get("second")
+		var txErr *types.TxFailedError
+		assert.True(t, errors.As(second.Err, &txErr))
+		assert.ErrorContains(t, outcome.Err(), "second")
+	})
+
+	t.Run("DependencyFailureSkips", func(t *testing.T) {
+		waiter := &fakeTxWaiter{}
+		tracker := tnclient.NewTxTracker(waiter.wait)
+		submitted := false
+		tracker.Submit("deploy", func(ctx context.Context) (transactions.TxHash, error) {
+			return transactions.TxHash{0xff}, nil
+		})
+		tracker.Submit("initialize", func(ctx context.Context) (transactions.TxHash, error) {
+			submitted = true
+			return transactions.TxHash{2}, nil
+		}, "deploy")
+
+		outcome, err := tracker.Wait(ctx)
+		require.NoError(t, err, "Wait should not fail")
+		assert.False(t, submitted, "dependent transaction should not be sent")
+		initialize, _ := outcome.Get("initialize")
+		assert.True(t, initialize.Skipped)
+		assert.True(t, errors.Is(initialize.Err, tnclient.ErrorTxDependencyFailed))
+	})
+
+	t.Run("DependenciesAreWaitedFirst", func(t *testing.T) {
+		waiter := &fakeTxWaiter{}
+		tracker := tnclient.NewTxTracker(waiter.wait)
+		tracker.Submit("third", func(ctx context.Context) (transactions.TxHash, error) {
+			return transactions.TxHash{3}, nil
+		}, "second")
+		tracker.Submit("second", func(ctx context.Context) (transactions.TxHash, error) {
+			return transactions.TxHash{2}, nil
+		}, "first")
+		tracker.Track("first", transactions.TxHash{1})
+
+		outcome, err := tracker.Wait(ctx)
+		require.NoError(t, err, "Wait should not fail")
+		assert.True(t, outcome.Succeeded())
+		assert.Equal(t, []transactions.TxHash{{1}, {2}, {3}}, waiter.waited)
+		assert.Equal(t, "third", outcome.Results[0].Label)
+	})
+
+	t.Run("InvalidDependencies", func(t *testing.T) {
+		waiter := &fakeTxWaiter{}
+		submit := func(ctx context.Context) (transactions.TxHash, error) {
+			return transactions.TxHash{1}, nil
+		}
+
+		unknown := tnclient.NewTxTracker(waiter.wait)
+		unknown.Submit("a", submit, "missing")
+		_, err := unknown.Wait(ctx)
+		assert.ErrorContains(t, err, "unknown transaction dependency")
+
+		cycle := tnclient.NewTxTracker(waiter.wait)
+		cycle.Submit("a", submit, "b")
+		cycle.Submit("b", submit, "a")
+		_, err = cycle.Wait(ctx)
+		assert.ErrorContains(t, err, "cycle")
+		assert.Empty(t, waiter.waited)
+	})
+
+	t.Run("WaitOnce", func(t *testing.T) {
+		waiter := &fakeTxWaiter{}
+		submitted := 0
+		tracker := tnclient.NewTxTracker(waiter.wait)
+		tracker.Submit("a", func(ctx context.Context) (transactions.TxHash, error) {
+			submitted++
+			return transactions.TxHash{1}, nil
+		})
+
+		outcome, err := tracker.Wait(ctx)
+		require.NoError(t, err, "Wait should not fail")
+		assert.True(t, outcome.Succeeded())
+
+		_, err = tracker.Wait(ctx)
+		assert.ErrorIs(t, err, tnclient.ErrorTxTrackerWaited)
+		assert.Equal(t, 1, submitted)
+	})
+}
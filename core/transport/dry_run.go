@@ -0,0 +1,220 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"sync"
+	"time"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/kwilteam/kwil-db/core/utils"
+	"github.com/pkg/errors"
+)
+
+var ErrorDryRunTx = errors.New("transaction was not broadcast, it's from a dry run")
+
+// DryRunTx is a transaction that would have been broadcast
+type DryRunTx struct {
+	// Operation is the kwil client method, i.e. "Execute" or "DeployDatabase"
+	Operation string
+	// Hash is the hash the transaction would have had
+	Hash transactions.TxHash
+	Tx   *transactions.Transaction
+}
+
+// DryRun collects the transactions of dry-run writes
+type DryRun struct {
+	mu  sync.Mutex
+	txs []DryRunTx
+}
+
+func NewDryRun() *DryRun {
+	return &DryRun{}
+}
+
+// Transactions returns the collected transactions, in the order they were built
+func (d *DryRun) Transactions() []DryRunTx {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]DryRunTx(nil), d.txs...)
+}
+
+func (d *DryRun) record(tx DryRunTx) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.txs = append(d.txs, tx)
+}
+
+func (d *DryRun) has(txHash []byte) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, tx := range d.txs {
+		if bytes.Equal(tx.Hash, txHash) {
+			return true
+		}
+	}
+	return false
+}
+
+type dryRunKey struct{}
+
+// WithDryRun makes the writes under the context dry runs, collected by dryRun
+func WithDryRun(ctx context.Context, dryRun *DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRun)
+}
+
+// TxBuilder signs transactions without broadcasting them. The kwil client is one
+type TxBuilder interface {
+	NewSignedTx(ctx context.Context, data transactions.Payload, txOpts *kwilClientType.TxOptions) (*transactions.Transaction, error)
+}
+
+// DryRunClient builds and signs transactions instead of broadcasting them, when a dry run
+// is set for the client or the context. Otherwise, it broadcasts as usual
+type DryRunClient struct {
+	kwilClientType.Client
+	builder  TxBuilder
+	identity []byte
+	// dryRun is set if every write of the client is a dry run
+	dryRun *DryRun
+}
+
+var _ kwilClientType.Client = (*DryRunClient)(nil)
+
+// NewDryRunClient wraps inner. dryRun may be nil, so only writes under WithDryRun are dry runs
+func NewDryRunClient(inner kwilClientType.Client, builder TxBuilder, identity []byte, dryRun *DryRun) *DryRunClient {
+	return &DryRunClient{
+		Client:   inner,
+		builder:  builder,
+		identity: identity,
+		dryRun:   dryRun,
+	}
+}
+
+// active returns the dry run of the context, or the one of the client
+func (d *DryRunClient) active(ctx context.Context) *DryRun {
+	if dryRun, ok := ctx.Value(dryRunKey{}).(*DryRun); ok && dryRun != nil {
+		return dryRun
+	}
+	return d.dryRun
+}
+
+// DryRunCaller returns the identity signing dry-run writes, or nil if writes under the context are broadcast
+func DryRunCaller(ctx context.Context, c kwilClientType.Client) []byte {
+	d, ok := c.(*DryRunClient)
+	if !ok || d.active(ctx) == nil {
+		return nil
+	}
+	return d.identity
+}
+
+func (d *DryRunClient) build(ctx context.Context, dryRun *DryRun, operation string, payload transactions.Payload, opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+	tx, err := d.builder.NewSignedTx(ctx, payload, kwilClientType.GetTxOpts(opts))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	serialized, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	hash := sha256.Sum256(serialized)
+
+	dryRun.record(DryRunTx{
+		Operation: operation,
+		Hash:      hash[:],
+		Tx:        tx,
+	})
+	return hash[:], nil
+}
+
+func encodeTuples(tuples [][]any) ([][]*transactions.EncodedValue, error) {
+	encodedTuples := make([][]*transactions.EncodedValue, len(tuples))
+	for i, tuple := range tuples {
+		encodedTuples[i] = make([]*transactions.EncodedValue, len(tuple))
+		for j, value := range tuple {
+			encoded, err := transactions.EncodeValue(value)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			encodedTuples[i][j] = encoded
+		}
+	}
+	return encodedTuples, nil
+}
+
+func (d *DryRunClient) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	dryRun := d.active(ctx)
+	if dryRun == nil {
+		return d.Client.Execute(ctx, dbid, action, tuples, opts...)
+	}
+
+	encodedTuples, err := encodeTuples(tuples)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return d.build(ctx, dryRun, "Execute", &transactions.ActionExecution{
+		Action:    action,
+		DBID:      dbid,
+		Arguments: encodedTuples,
+	}, opts)
+}
+
+func (d *DryRunClient) ExecuteAction(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	if d.active(ctx) == nil {
+		return d.Client.ExecuteAction(ctx, dbid, action, tuples, opts...)
+	}
+	return d.Execute(ctx, dbid, action, tuples, opts...)
+}
+
+func (d *DryRunClient) DeployDatabase(ctx context.Context, payload *kwiltypes.Schema, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	dryRun := d.active(ctx)
+	if dryRun == nil {
+		return d.Client.DeployDatabase(ctx, payload, opts...)
+	}
+
+	schema := &transactions.Schema{}
+	schema.FromTypes(payload)
+	return d.build(ctx, dryRun, "DeployDatabase", schema, opts)
+}
+
+func (d *DryRunClient) DropDatabase(ctx context.Context, name string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	if d.active(ctx) == nil {
+		return d.Client.DropDatabase(ctx, name, opts...)
+	}
+	return d.DropDatabaseID(ctx, utils.GenerateDBID(name, d.identity), opts...)
+}
+
+func (d *DryRunClient) DropDatabaseID(ctx context.Context, dbid string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	dryRun := d.active(ctx)
+	if dryRun == nil {
+		return d.Client.DropDatabaseID(ctx, dbid, opts...)
+	}
+	return d.build(ctx, dryRun, "DropDatabaseID", &transactions.DropSchema{DBID: dbid}, opts)
+}
+
+func (d *DryRunClient) Transfer(ctx context.Context, to []byte, amount *big.Int, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	dryRun := d.active(ctx)
+	if dryRun == nil {
+		return d.Client.Transfer(ctx, to, amount, opts...)
+	}
+	return d.build(ctx, dryRun, "Transfer", &transactions.Transfer{
+		To:     to,
+		Amount: amount.String(),
+	}, opts)
+}
+
+// WaitTx fails right away for dry-run transactions, as they will never be mined
+func (d *DryRunClient) WaitTx(ctx context.Context, txHash []byte, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	if dryRun := d.active(ctx); dryRun != nil && dryRun.has(txHash) {
+		return nil, errors.Wrap(ErrorDryRunTx, transactions.TxHash(txHash).Hex())
+	}
+	return d.Client.WaitTx(ctx, txHash, interval)
+}
@@ -0,0 +1,34 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// TestDryRunContext checks that a dry run set on the context collects the transaction instead of broadcasting it.
+func TestDryRunContext(t *testing.T) {
+	ctx := context.Background()
+	caller := []byte{1, 2, 3}
+	node := &kwiltest.Client{}
+	client := transport.NewDryRunClient(node, node, caller, nil)
+	dryRun := transport.NewDryRun()
+	dryCtx := transport.WithDryRun(ctx, dryRun)
+
+	assert.Nil(t, transport.DryRunCaller(ctx, client))
+	assert.Equal(t, caller, transport.DryRunCaller(dryCtx, client))
+
+	txHash, err := client.Execute(dryCtx, "dbid", "insert_record", [][]any{{"2024-01-01", "1"}})
+	require.NoError(t, err, "Dry run should succeed")
+	assert.Equal(t, 1, len(dryRun.Transactions()))
+	assert.Equal(t, 0, node.Requests("Execute"), "dry runs should not broadcast")
+
+	_, err = client.WaitTx(dryCtx, txHash, time.Millisecond)
+	assert.True(t, errors.Is(err, transport.ErrorDryRunTx))
+}
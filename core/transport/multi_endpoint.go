@@ -0,0 +1,384 @@
+package transport
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+)
+
+var ErrorNoEndpoint = errors.New("no endpoint available")
+
+// Endpoint is a node of a MultiEndpointClient
+type Endpoint struct {
+	// Name identifies the endpoint in errors and health events, i.e. its URL
+	Name string
+	// Connect creates the client of the endpoint. If it fails, health checks call it again until it succeeds
+	Connect func(ctx context.Context) (kwilClientType.Client, error)
+	// ReadOnly endpoints never get broadcasts
+	ReadOnly bool
+	// ReadPreference orders the endpoints for view calls, lower first
+	ReadPreference int
+	// WritePreference orders the endpoints for broadcasts, lower first
+	WritePreference int
+}
+
+// MultiEndpointOptions configures health checks and failover.
+// Zero values are replaced by the defaults of DefaultMultiEndpointOptions
+type MultiEndpointOptions struct {
+	// HealthCheckInterval is the time between health checks of every endpoint
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout limits each health check
+	HealthCheckTimeout time.Duration
+	// IsFailover tells if a failed view call can be tried on the next endpoint
+	IsFailover func(err error) bool
+	// IsBroadcastFailover tells if a failed broadcast can be sent to the next endpoint. It should only accept
+	// errors where the transaction surely didn't reach the node, otherwise it could be sent twice
+	IsBroadcastFailover func(err error) bool
+	// OnHealthChange optional. Called when an endpoint becomes healthy or unhealthy
+	OnHealthChange func(event HealthEvent)
+}
+
+// HealthEvent describes a change of health of an endpoint
+type HealthEvent struct {
+	Endpoint string
+	Healthy  bool
+	// Err is why the endpoint is unhealthy
+	Err error
+}
+
+func DefaultMultiEndpointOptions() MultiEndpointOptions {
+	return MultiEndpointOptions{
+		HealthCheckInterval: 10 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		IsFailover:          IsTransientError,
+		IsBroadcastFailover: IsConnectionRefused,
+	}
+}
+
+func (o MultiEndpointOptions) withDefaults() MultiEndpointOptions {
+	defaults := DefaultMultiEndpointOptions()
+	if o.HealthCheckInterval <= 0 {
+		o.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+	if o.HealthCheckTimeout <= 0 {
+		o.HealthCheckTimeout = defaults.HealthCheckTimeout
+	}
+	if o.IsFailover == nil {
+		o.IsFailover = defaults.IsFailover
+	}
+	if o.IsBroadcastFailover == nil {
+		o.IsBroadcastFailover = defaults.IsBroadcastFailover
+	}
+	return o
+}
+
+type endpointState struct {
+	Endpoint
+	mu      sync.RWMutex
+	client  kwilClientType.Client
+	healthy bool
+}
+
+func (e *endpointState) get() (kwilClientType.Client, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.client, e.healthy
+}
+
+// MultiEndpointClient spreads requests over many nodes. View calls fail over to the next endpoint on
+// transient errors. Broadcasts only do so if the transaction surely didn't reach the node.
+// Endpoints are checked in the background until Close is called
+type MultiEndpointClient struct {
+	options   MultiEndpointOptions
+	endpoints []*endpointState
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ kwilClientType.Client = (*MultiEndpointClient)(nil)
+
+// NewMultiEndpointClient connects to every endpoint. It fails only if none can be connected
+func NewMultiEndpointClient(ctx context.Context, endpoints []Endpoint, options MultiEndpointOptions) (*MultiEndpointClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.WithStack(ErrorNoEndpoint)
+	}
+
+	m := &MultiEndpointClient{
+		options: options.withDefaults(),
+		stop:    make(chan struct{}),
+	}
+	for _, endpoint := range endpoints {
+		m.endpoints = append(m.endpoints, &endpointState{Endpoint: endpoint})
+	}
+
+	connected := false
+	for _, endpoint := range m.endpoints {
+		m.check(ctx, endpoint)
+		if client, _ := endpoint.get(); client != nil {
+			connected = true
+		}
+	}
+	if !connected {
+		return nil, errors.Wrap(ErrorNoEndpoint, "no endpoint could be connected")
+	}
+
+	m.wg.Add(1)
+	go m.healthCheckLoop()
+
+	return m, nil
+}
+
+// Close stops the health checks
+func (m *MultiEndpointClient) Close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	m.wg.Wait()
+}
+
+// Healthy returns the names of the healthy endpoints
+func (m *MultiEndpointClient) Healthy() []string {
+	var names []string
+	for _, endpoint := range m.endpoints {
+		if _, healthy := endpoint.get(); healthy {
+			names = append(names, endpoint.Name)
+		}
+	}
+	return names
+}
+
+func (m *MultiEndpointClient) healthCheckLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			for _, endpoint := range m.endpoints {
+				ctx, cancel := context.WithTimeout(context.Background(), m.options.HealthCheckTimeout)
+				m.check(ctx, endpoint)
+				cancel()
+			}
+		}
+	}
+}
+
+// check connects to the endpoint if needed, and pings it
+func (m *MultiEndpointClient) check(ctx context.Context, endpoint *endpointState) {
+	client, _ := endpoint.get()
+
+	var err error
+	if client == nil {
+		client, err = endpoint.Connect(ctx)
+		if err == nil {
+			endpoint.mu.Lock()
+			endpoint.client = client
+			endpoint.mu.Unlock()
+		}
+	} else {
+		_, err = client.Ping(ctx)
+	}
+
+	m.setHealth(endpoint, err)
+}
+
+func (m *MultiEndpointClient) setHealth(endpoint *endpointState, err error) {
+	healthy := err == nil
+
+	endpoint.mu.Lock()
+	changed := endpoint.healthy != healthy
+	endpoint.healthy = healthy
+	endpoint.mu.Unlock()
+
+	if changed && m.options.OnHealthChange != nil {
+		m.options.OnHealthChange(HealthEvent{
+			Endpoint: endpoint.Name,
+			Healthy:  healthy,
+			Err:      err,
+		})
+	}
+}
+
+// candidates orders the connected endpoints by preference, healthy ones first.
+// Unhealthy ones are kept as a last resort, as health checks may be outdated
+func (m *MultiEndpointClient) candidates(write bool) []*endpointState {
+	var healthy, unhealthy []*endpointState
+	for _, endpoint := range m.endpoints {
+		if write && endpoint.ReadOnly {
+			continue
+		}
+		client, isHealthy := endpoint.get()
+		switch {
+		case client == nil:
+		case isHealthy:
+			healthy = append(healthy, endpoint)
+		default:
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	preference := func(endpoints []*endpointState) {
+		sort.SliceStable(endpoints, func(i, j int) bool {
+			if write {
+				return endpoints[i].WritePreference < endpoints[j].WritePreference
+			}
+			return endpoints[i].ReadPreference < endpoints[j].ReadPreference
+		})
+	}
+	preference(healthy)
+	preference(unhealthy)
+
+	return append(healthy, unhealthy...)
+}
+
+// route tries the endpoints in order, moving to the next one only if isFailover accepts the error
+func route[T any](m *MultiEndpointClient, write bool, isFailover func(err error) bool, fn func(client kwilClientType.Client) (T, error)) (T, error) {
+	var result T
+	err := errors.WithStack(ErrorNoEndpoint)
+
+	for _, endpoint := range m.candidates(write) {
+		client, _ := endpoint.get()
+		result, err = fn(client)
+		if err == nil || !isFailover(err) {
+			return result, err
+		}
+		m.setHealth(endpoint, err)
+	}
+
+	return result, err
+}
+
+func view[T any](m *MultiEndpointClient, fn func(client kwilClientType.Client) (T, error)) (T, error) {
+	return route(m, false, m.options.IsFailover, fn)
+}
+
+func broadcast(m *MultiEndpointClient, fn func(client kwilClientType.Client) (transactions.TxHash, error)) (transactions.TxHash, error) {
+	return route(m, true, m.options.IsBroadcastFailover, fn)
+}
+
+// ChainID is the one of the preferred write endpoint, as all of them are checked to be on the same chain
+func (m *MultiEndpointClient) ChainID() string {
+	candidates := m.candidates(true)
+	if len(candidates) == 0 {
+		candidates = m.candidates(false)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	client, _ := candidates[0].get()
+	return client.ChainID()
+}
+
+// ## View calls
+
+func (m *MultiEndpointClient) Call(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+	return view(m, func(client kwilClientType.Client) (*kwilClientType.Records, error) {
+		return client.Call(ctx, dbid, procedure, inputs)
+	})
+}
+
+func (m *MultiEndpointClient) CallAction(ctx context.Context, dbid string, action string, inputs []any) (*kwilClientType.Records, error) {
+	return view(m, func(client kwilClientType.Client) (*kwilClientType.Records, error) {
+		return client.CallAction(ctx, dbid, action, inputs)
+	})
+}
+
+func (m *MultiEndpointClient) Query(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+	return view(m, func(client kwilClientType.Client) (*kwilClientType.Records, error) {
+		return client.Query(ctx, dbid, query)
+	})
+}
+
+func (m *MultiEndpointClient) GetSchema(ctx context.Context, dbid string) (*kwiltypes.Schema, error) {
+	return view(m, func(client kwilClientType.Client) (*kwiltypes.Schema, error) {
+		return client.GetSchema(ctx, dbid)
+	})
+}
+
+func (m *MultiEndpointClient) ListDatabases(ctx context.Context, owner []byte) ([]*kwiltypes.DatasetIdentifier, error) {
+	return view(m, func(client kwilClientType.Client) ([]*kwiltypes.DatasetIdentifier, error) {
+		return client.ListDatabases(ctx, owner)
+	})
+}
+
+// GetAccount prefers the write endpoints, as the pending nonce depends on the mempool of the node
+func (m *MultiEndpointClient) GetAccount(ctx context.Context, pubKey []byte, status kwiltypes.AccountStatus) (*kwiltypes.Account, error) {
+	return route(m, true, m.options.IsFailover, func(client kwilClientType.Client) (*kwiltypes.Account, error) {
+		return client.GetAccount(ctx, pubKey, status)
+	})
+}
+
+func (m *MultiEndpointClient) TxQuery(ctx context.Context, txHash []byte) (*transactions.TcTxQueryResponse, error) {
+	return view(m, func(client kwilClientType.Client) (*transactions.TcTxQueryResponse, error) {
+		return client.TxQuery(ctx, txHash)
+	})
+}
+
+func (m *MultiEndpointClient) WaitTx(ctx context.Context, txHash []byte, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	return view(m, func(client kwilClientType.Client) (*transactions.TcTxQueryResponse, error) {
+		return client.WaitTx(ctx, txHash, interval)
+	})
+}
+
+func (m *MultiEndpointClient) ChainInfo(ctx context.Context) (*kwiltypes.ChainInfo, error) {
+	return view(m, func(client kwilClientType.Client) (*kwiltypes.ChainInfo, error) {
+		return client.ChainInfo(ctx)
+	})
+}
+
+func (m *MultiEndpointClient) Ping(ctx context.Context) (string, error) {
+	return view(m, func(client kwilClientType.Client) (string, error) {
+		return client.Ping(ctx)
+	})
+}
+
+// ## Broadcasts
+
+func (m *MultiEndpointClient) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.Execute(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (m *MultiEndpointClient) ExecuteAction(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.ExecuteAction(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (m *MultiEndpointClient) DeployDatabase(ctx context.Context, payload *kwiltypes.Schema, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.DeployDatabase(ctx, payload, opts...)
+	})
+}
+
+func (m *MultiEndpointClient) DropDatabase(ctx context.Context, name string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.DropDatabase(ctx, name, opts...)
+	})
+}
+
+func (m *MultiEndpointClient) DropDatabaseID(ctx context.Context, dbid string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.DropDatabaseID(ctx, dbid, opts...)
+	})
+}
+
+func (m *MultiEndpointClient) Transfer(ctx context.Context, to []byte, amount *big.Int, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return broadcast(m, func(client kwilClientType.Client) (transactions.TxHash, error) {
+		return client.Transfer(ctx, to, amount, opts...)
+	})
+}
@@ -0,0 +1,118 @@
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+func connected(client kwilClientType.Client) func(ctx context.Context) (kwilClientType.Client, error) {
+	return func(ctx context.Context) (kwilClientType.Client, error) {
+		return client, nil
+	}
+}
+
+// TestMultiEndpointClient checks failover of view calls and broadcasts, and the health checks of endpoints.
+func TestMultiEndpointClient(t *testing.T) {
+	ctx := context.Background()
+	connRefused := fmt.Errorf("http post failed: %w", syscall.ECONNREFUSED)
+	options := transport.MultiEndpointOptions{HealthCheckInterval: time.Hour}
+
+	t.Run("ViewFailover", func(t *testing.T) {
+		primary, secondary := &kwiltest.Client{}, &kwiltest.Client{}
+		var events []transport.HealthEvent
+		opts := options
+		opts.OnHealthChange = func(event transport.HealthEvent) {
+			events = append(events, event)
+		}
+		client, err := transport.NewMultiEndpointClient(ctx, []transport.Endpoint{
+			{Name: "primary", Connect: connected(primary)},
+			{Name: "secondary", Connect: connected(secondary), ReadPreference: 1},
+		}, opts)
+		require.NoError(t, err, "Failed to create client")
+		defer client.Close()
+
+		primary.FailWith(connRefused)
+		_, err = client.Call(ctx, "dbid", "get_record", nil)
+		require.NoError(t, err, "Call should fail over to the secondary")
+		assert.Equal(t, 1, secondary.Requests("Call"))
+		assert.Equal(t, []string{"secondary"}, client.Healthy())
+
+		// the unhealthy primary is not tried first anymore
+		_, err = client.Call(ctx, "dbid", "get_record", nil)
+		require.NoError(t, err, "Call should succeed")
+		assert.Equal(t, 1, primary.Requests("Call"))
+
+		// contract errors are not failed over
+		secondary.FailWith(errors.New("ERROR: wallet not allowed to read"))
+		_, err = client.Call(ctx, "dbid", "get_record", nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, primary.Requests("Call"))
+
+		if assert.Equal(t, 3, len(events), "both endpoints become healthy, then the primary unhealthy") {
+			assert.Equal(t, "primary", events[2].Endpoint)
+			assert.False(t, events[2].Healthy)
+		}
+	})
+
+	t.Run("BroadcastRouting", func(t *testing.T) {
+		primary, readOnly, secondary := &kwiltest.Client{}, &kwiltest.Client{}, &kwiltest.Client{}
+		client, err := transport.NewMultiEndpointClient(ctx, []transport.Endpoint{
+			{Name: "primary", Connect: connected(primary)},
+			{Name: "read-only", Connect: connected(readOnly), ReadOnly: true},
+			{Name: "secondary", Connect: connected(secondary), WritePreference: 1},
+		}, options)
+		require.NoError(t, err, "Failed to create client")
+		defer client.Close()
+
+		// the node may have received the transaction, so it's not sent elsewhere
+		primary.FailWith(errors.New("Service Unavailable"))
+		_, err = client.Execute(ctx, "dbid", "insert_record", nil)
+		assert.Error(t, err)
+		assert.Equal(t, 0, secondary.Requests("Execute"))
+
+		// the node surely didn't receive it
+		primary.FailWith(connRefused)
+		_, err = client.Execute(ctx, "dbid", "insert_record", nil)
+		require.NoError(t, err, "Execute should fail over to the secondary")
+		assert.Equal(t, 1, secondary.Requests("Execute"))
+		assert.Equal(t, 0, readOnly.Requests("Execute"))
+	})
+
+	t.Run("HealthCheckReconnects", func(t *testing.T) {
+		primary, late := &kwiltest.Client{}, &kwiltest.Client{}
+		var mu sync.Mutex
+		up := false
+		client, err := transport.NewMultiEndpointClient(ctx, []transport.Endpoint{
+			{Name: "primary", Connect: connected(primary)},
+			{Name: "late", Connect: func(ctx context.Context) (kwilClientType.Client, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if !up {
+					return nil, connRefused
+				}
+				return late, nil
+			}},
+		}, transport.MultiEndpointOptions{HealthCheckInterval: 5 * time.Millisecond})
+		require.NoError(t, err, "Failed to create client")
+		defer client.Close()
+		assert.Equal(t, []string{"primary"}, client.Healthy())
+
+		mu.Lock()
+		up = true
+		mu.Unlock()
+		assert.Eventually(t, func() bool {
+			return len(client.Healthy()) == 2
+		}, time.Second, 5*time.Millisecond)
+	})
+}
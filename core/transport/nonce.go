@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+)
+
+// defaultNonceAttempts is how many nonces a broadcast may try before giving up on nonce errors
+const defaultNonceAttempts = 5
+
+// NonceClient assigns nonces to broadcasts locally, so goroutines sharing a signer don't get the
+// same nonce from the node. The pending nonce is fetched once, and again after a broadcast fails
+type NonceClient struct {
+	kwilClientType.Client
+	identity []byte
+
+	mu     sync.Mutex
+	synced bool
+	// last is the last nonce handed out
+	last int64
+	// generation grows on every fetch, so concurrent failures resync only once
+	generation int
+}
+
+var _ kwilClientType.Client = (*NonceClient)(nil)
+
+func NewNonceClient(inner kwilClientType.Client, identity []byte) *NonceClient {
+	return &NonceClient{
+		Client:   inner,
+		identity: identity,
+	}
+}
+
+// nextNonce hands out the next nonce, fetching the pending one from the node if needed
+func (n *NonceClient) nextNonce(ctx context.Context) (int64, int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.synced {
+		account, err := n.Client.GetAccount(ctx, n.identity, kwiltypes.AccountStatusPending)
+		if err != nil {
+			return 0, 0, errors.WithStack(err)
+		}
+		n.last = account.Nonce
+		n.synced = true
+		n.generation++
+	}
+
+	n.last++
+	return n.last, n.generation, nil
+}
+
+// resync makes the next broadcast fetch the nonce again, unless it was already fetched
+// after the given generation
+func (n *NonceClient) resync(generation int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.generation == generation {
+		n.synced = false
+	}
+}
+
+// Resync drops the local nonce, i.e. after the same wallet was used somewhere else
+func (n *NonceClient) Resync() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.synced = false
+}
+
+// broadcast sends with a local nonce, unless the caller set one.
+// On nonce errors, i.e. a concurrent broadcast reached the node first, it's sent again with a fresh nonce
+func (n *NonceClient) broadcast(ctx context.Context, opts []kwilClientType.TxOpt, fn func(opts []kwilClientType.TxOpt) (transactions.TxHash, error)) (transactions.TxHash, error) {
+	if n.identity == nil {
+		return fn(opts)
+	}
+
+	txOpts := &kwilClientType.TxOptions{}
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+	if txOpts.Nonce > 0 {
+		return fn(opts)
+	}
+
+	for attempt := 1; ; attempt++ {
+		nonce, generation, err := n.nextNonce(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		txHash, err := fn(append(opts, kwilClientType.WithNonce(nonce)))
+		if err == nil {
+			return txHash, nil
+		}
+
+		// the nonce may not have been used, so later ones would be rejected
+		n.resync(generation)
+
+		if !errors.Is(err, transactions.ErrInvalidNonce) || attempt >= defaultNonceAttempts {
+			return nil, err
+		}
+	}
+}
+
+func (n *NonceClient) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.Execute(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (n *NonceClient) ExecuteAction(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.ExecuteAction(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (n *NonceClient) DeployDatabase(ctx context.Context, payload *kwiltypes.Schema, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.DeployDatabase(ctx, payload, opts...)
+	})
+}
+
+func (n *NonceClient) DropDatabase(ctx context.Context, name string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.DropDatabase(ctx, name, opts...)
+	})
+}
+
+func (n *NonceClient) DropDatabaseID(ctx context.Context, dbid string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.DropDatabaseID(ctx, dbid, opts...)
+	})
+}
+
+func (n *NonceClient) Transfer(ctx context.Context, to []byte, amount *big.Int, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return n.broadcast(ctx, opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return n.Client.Transfer(ctx, to, amount, opts...)
+	})
+}
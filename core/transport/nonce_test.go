@@ -0,0 +1,99 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// nonceNode accepts each nonce once, like the mempool of a node
+type nonceNode struct {
+	*kwiltest.Client
+	mu           sync.Mutex
+	accountNonce int64
+	used         map[int64]bool
+}
+
+func newNonceNode(accountNonce int64) *nonceNode {
+	node := &nonceNode{accountNonce: accountNonce, used: map[int64]bool{}}
+	node.Client = &kwiltest.Client{
+		GetAccountFunc: func(ctx context.Context, pubKey []byte) (*kwiltypes.Account, error) {
+			node.mu.Lock()
+			defer node.mu.Unlock()
+			return &kwiltypes.Account{Nonce: node.accountNonce}, nil
+		},
+		ExecuteFunc: func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error) {
+			node.mu.Lock()
+			defer node.mu.Unlock()
+			if txOpts.Nonce <= node.accountNonce || node.used[txOpts.Nonce] {
+				return nil, errors.Join(transactions.ErrInvalidNonce, errors.New("broadcast failed"))
+			}
+			node.used[txOpts.Nonce] = true
+			return transactions.TxHash{byte(txOpts.Nonce)}, nil
+		},
+	}
+	return node
+}
+
+// TestNonceClient checks that concurrent broadcasts from one signer get distinct nonces,
+// and that nonces are fetched again after a nonce error.
+func TestNonceClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ConcurrentBroadcasts", func(t *testing.T) {
+		node := newNonceNode(7)
+		client := transport.NewNonceClient(node, []byte{1})
+
+		var wg sync.WaitGroup
+		errs := make([]error, 20)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = client.Execute(ctx, "dbid", "insert_record", nil)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 20, len(node.used))
+		assert.True(t, node.used[8] && node.used[27])
+		assert.Equal(t, 1, node.Requests("GetAccount"), "the nonce should be fetched once")
+	})
+
+	t.Run("ResyncOnNonceError", func(t *testing.T) {
+		node := newNonceNode(7)
+		client := transport.NewNonceClient(node, []byte{1})
+
+		_, err := client.Execute(ctx, "dbid", "insert_record", nil)
+		require.NoError(t, err, "first broadcast should succeed")
+
+		// the same wallet broadcasts from somewhere else
+		node.accountNonce = 12
+		txHash, err := client.Execute(ctx, "dbid", "insert_record", nil)
+		require.NoError(t, err, "broadcast should succeed after resync")
+		assert.Equal(t, transactions.TxHash{13}, txHash)
+		assert.Equal(t, 2, node.Requests("GetAccount"))
+	})
+
+	t.Run("CallerNonceIsKept", func(t *testing.T) {
+		node := newNonceNode(7)
+		client := transport.NewNonceClient(node, []byte{1})
+
+		txHash, err := client.Execute(ctx, "dbid", "insert_record", nil, kwilClientType.WithNonce(30))
+		require.NoError(t, err, "broadcast should succeed")
+		assert.Equal(t, transactions.TxHash{30}, txHash)
+		assert.Equal(t, 0, node.Requests("GetAccount"))
+	})
+}
@@ -0,0 +1,324 @@
+// Package transport has decorators of the kwil client, which add behavior to every
+// call and broadcast made by the SDK
+package transport
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kwilteam/kwil-db/core/rpc/client"
+	jsonrpc "github.com/kwilteam/kwil-db/core/rpc/json"
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy configures how failed requests are retried.
+// Zero values are replaced by the defaults of DefaultRetryPolicy
+type RetryPolicy struct {
+	// MaxAttempts including the first one. 1 disables retries
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt
+	Multiplier float64
+	// Jitter is the fraction of the delay randomly added or removed, from 0 to 1
+	Jitter float64
+	// IsRetryable tells if a failed view call can be retried
+	IsRetryable func(err error) bool
+	// IsBroadcastRetryable tells if a failed broadcast can be retried. It should only accept errors
+	// where the transaction surely didn't reach the node
+	IsBroadcastRetryable func(err error) bool
+	// OnRetry optional. Called before waiting for every retry
+	OnRetry func(event RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is going to be retried
+type RetryEvent struct {
+	// Operation is the kwil client method, i.e. "Call" or "Execute"
+	Operation string
+	// Attempt is the number of the failed attempt, starting at 1
+	Attempt int
+	// Delay until the next attempt
+	Delay time.Duration
+	Err   error
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          4,
+		InitialBackoff:       200 * time.Millisecond,
+		MaxBackoff:           5 * time.Second,
+		Multiplier:           2,
+		Jitter:               0.2,
+		IsRetryable:          IsTransientError,
+		IsBroadcastRetryable: IsConnectionRefused,
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = defaults.Jitter
+	}
+	if p.IsRetryable == nil {
+		p.IsRetryable = defaults.IsRetryable
+	}
+	if p.IsBroadcastRetryable == nil {
+		p.IsBroadcastRetryable = defaults.IsBroadcastRetryable
+	}
+	return p
+}
+
+// Backoff returns the delay before the given retry, starting at 1, without jitter
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	p = p.withDefaults()
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	if delay > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(delay)
+}
+
+func (p RetryPolicy) jittered(delay time.Duration) time.Duration {
+	if p.Jitter == 0 {
+		return delay
+	}
+	factor := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(delay) * factor)
+}
+
+// IsTransientError tells if the error is likely to go away by itself: network errors,
+// gateway errors, rate limits and node timeouts. Context errors are never transient
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var rpcErr *client.RPCError
+	if errors.As(err, &rpcErr) {
+		switch jsonrpc.ErrorCode(rpcErr.Code) {
+		case jsonrpc.ErrorTimeout, jsonrpc.ErrorKGWTooManyRequests:
+			return true
+		}
+	}
+
+	// the rpc client only keeps the status text of responses without a body
+	message := err.Error()
+	for _, status := range []string{"Bad Gateway", "Service Unavailable", "Gateway Timeout", "Too Many Requests"} {
+		if strings.Contains(message, status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsConnectionRefused tells if the node couldn't be reached at all, so nothing was sent
+func IsConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// RetryClient retries failed requests of the wrapped client according to a policy.
+// Broadcasts are retried with the same nonce, so a transaction that did reach the node can't be applied twice
+type RetryClient struct {
+	kwilClientType.Client
+	policy RetryPolicy
+	// identity of the signer, used to pin the nonce of broadcasts. Broadcasts are not retried without it
+	identity []byte
+}
+
+var _ kwilClientType.Client = (*RetryClient)(nil)
+
+func NewRetryClient(inner kwilClientType.Client, policy RetryPolicy, identity []byte) *RetryClient {
+	return &RetryClient{
+		Client:   inner,
+		policy:   policy.withDefaults(),
+		identity: identity,
+	}
+}
+
+func retry[T any](ctx context.Context, r *RetryClient, operation string, isRetryable func(error) bool, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	for attempt := 1; ; attempt++ {
+		result, err = fn()
+		if err == nil || attempt >= r.policy.MaxAttempts || !isRetryable(err) {
+			return result, err
+		}
+
+		delay := r.policy.jittered(r.policy.Backoff(attempt))
+		if r.policy.OnRetry != nil {
+			r.policy.OnRetry(RetryEvent{
+				Operation: operation,
+				Attempt:   attempt,
+				Delay:     delay,
+				Err:       err,
+			})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, errors.Wrap(ctx.Err(), err.Error())
+		case <-timer.C:
+		}
+	}
+}
+
+// ## View calls
+
+func (r *RetryClient) Call(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+	return retry(ctx, r, "Call", r.policy.IsRetryable, func() (*kwilClientType.Records, error) {
+		return r.Client.Call(ctx, dbid, procedure, inputs)
+	})
+}
+
+func (r *RetryClient) CallAction(ctx context.Context, dbid string, action string, inputs []any) (*kwilClientType.Records, error) {
+	return retry(ctx, r, "CallAction", r.policy.IsRetryable, func() (*kwilClientType.Records, error) {
+		return r.Client.CallAction(ctx, dbid, action, inputs)
+	})
+}
+
+func (r *RetryClient) Query(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+	return retry(ctx, r, "Query", r.policy.IsRetryable, func() (*kwilClientType.Records, error) {
+		return r.Client.Query(ctx, dbid, query)
+	})
+}
+
+func (r *RetryClient) GetSchema(ctx context.Context, dbid string) (*kwiltypes.Schema, error) {
+	return retry(ctx, r, "GetSchema", r.policy.IsRetryable, func() (*kwiltypes.Schema, error) {
+		return r.Client.GetSchema(ctx, dbid)
+	})
+}
+
+func (r *RetryClient) ListDatabases(ctx context.Context, owner []byte) ([]*kwiltypes.DatasetIdentifier, error) {
+	return retry(ctx, r, "ListDatabases", r.policy.IsRetryable, func() ([]*kwiltypes.DatasetIdentifier, error) {
+		return r.Client.ListDatabases(ctx, owner)
+	})
+}
+
+func (r *RetryClient) GetAccount(ctx context.Context, pubKey []byte, status kwiltypes.AccountStatus) (*kwiltypes.Account, error) {
+	return retry(ctx, r, "GetAccount", r.policy.IsRetryable, func() (*kwiltypes.Account, error) {
+		return r.Client.GetAccount(ctx, pubKey, status)
+	})
+}
+
+func (r *RetryClient) TxQuery(ctx context.Context, txHash []byte) (*transactions.TcTxQueryResponse, error) {
+	return retry(ctx, r, "TxQuery", r.policy.IsRetryable, func() (*transactions.TcTxQueryResponse, error) {
+		return r.Client.TxQuery(ctx, txHash)
+	})
+}
+
+func (r *RetryClient) WaitTx(ctx context.Context, txHash []byte, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	return retry(ctx, r, "WaitTx", r.policy.IsRetryable, func() (*transactions.TcTxQueryResponse, error) {
+		return r.Client.WaitTx(ctx, txHash, interval)
+	})
+}
+
+func (r *RetryClient) ChainInfo(ctx context.Context) (*kwiltypes.ChainInfo, error) {
+	return retry(ctx, r, "ChainInfo", r.policy.IsRetryable, func() (*kwiltypes.ChainInfo, error) {
+		return r.Client.ChainInfo(ctx)
+	})
+}
+
+func (r *RetryClient) Ping(ctx context.Context) (string, error) {
+	return retry(ctx, r, "Ping", r.policy.IsRetryable, func() (string, error) {
+		return r.Client.Ping(ctx)
+	})
+}
+
+// ## Broadcasts
+
+// broadcast pins the nonce before the first attempt, unless the caller already did,
+// so every attempt sends the same transaction
+func (r *RetryClient) broadcast(ctx context.Context, operation string, opts []kwilClientType.TxOpt, fn func(opts []kwilClientType.TxOpt) (transactions.TxHash, error)) (transactions.TxHash, error) {
+	if r.identity == nil || r.policy.MaxAttempts <= 1 {
+		return fn(opts)
+	}
+
+	txOpts := &kwilClientType.TxOptions{}
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+
+	if txOpts.Nonce <= 0 {
+		account, err := r.GetAccount(ctx, r.identity, kwiltypes.AccountStatusPending)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		opts = append(opts, kwilClientType.WithNonce(account.Nonce+1))
+	}
+
+	return retry(ctx, r, operation, r.policy.IsBroadcastRetryable, func() (transactions.TxHash, error) {
+		return fn(opts)
+	})
+}
+
+func (r *RetryClient) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "Execute", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.Execute(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (r *RetryClient) ExecuteAction(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "ExecuteAction", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.ExecuteAction(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (r *RetryClient) DeployDatabase(ctx context.Context, payload *kwiltypes.Schema, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "DeployDatabase", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.DeployDatabase(ctx, payload, opts...)
+	})
+}
+
+func (r *RetryClient) DropDatabase(ctx context.Context, name string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "DropDatabase", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.DropDatabase(ctx, name, opts...)
+	})
+}
+
+func (r *RetryClient) DropDatabaseID(ctx context.Context, dbid string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "DropDatabaseID", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.DropDatabaseID(ctx, dbid, opts...)
+	})
+}
+
+func (r *RetryClient) Transfer(ctx context.Context, to []byte, amount *big.Int, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return r.broadcast(ctx, "Transfer", opts, func(opts []kwilClientType.TxOpt) (transactions.TxHash, error) {
+		return r.Client.Transfer(ctx, to, amount, opts...)
+	})
+}
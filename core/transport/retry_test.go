@@ -0,0 +1,100 @@
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+)
+
+// retryNode fails the first view calls and broadcasts with errs, for a wallet at nonce 7
+func retryNode(errs ...error) *kwiltest.Client {
+	node := &kwiltest.Client{
+		GetAccountFunc: func(ctx context.Context, pubKey []byte) (*kwiltypes.Account, error) {
+			return &kwiltypes.Account{Nonce: 7}, nil
+		},
+	}
+	node.FailNext(errs...)
+	return node
+}
+
+// TestRetryClient checks which failures are retried, and that broadcasts keep their nonce.
+func TestRetryClient(t *testing.T) {
+	ctx := context.Background()
+	connRefused := fmt.Errorf("http post failed: %w", syscall.ECONNREFUSED)
+	policy := transport.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+
+	t.Run("TransientCallErrors", func(t *testing.T) {
+		fake := retryNode(connRefused, errors.New("Service Unavailable"))
+		var events []transport.RetryEvent
+		p := policy
+		p.OnRetry = func(event transport.RetryEvent) {
+			events = append(events, event)
+		}
+
+		_, err := transport.NewRetryClient(fake, p, []byte{1}).Call(ctx, "dbid", "get_record", nil)
+		require.NoError(t, err, "Call should succeed on the third attempt")
+		assert.Equal(t, 3, fake.Requests("Call"))
+		if assert.Equal(t, 2, len(events)) {
+			assert.Equal(t, "Call", events[0].Operation)
+			assert.Equal(t, 2, events[1].Attempt)
+		}
+	})
+
+	t.Run("GivesUp", func(t *testing.T) {
+		fake := retryNode(connRefused, connRefused, connRefused, connRefused)
+		_, err := transport.NewRetryClient(fake, policy, []byte{1}).Call(ctx, "dbid", "get_record", nil)
+		assert.Error(t, err)
+		assert.Equal(t, 3, fake.Requests("Call"))
+	})
+
+	t.Run("PermanentCallErrors", func(t *testing.T) {
+		fake := retryNode(errors.New("wallet not allowed to read"))
+		_, err := transport.NewRetryClient(fake, policy, []byte{1}).Call(ctx, "dbid", "get_record", nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, fake.Requests("Call"))
+	})
+
+	t.Run("BroadcastsKeepTheirNonce", func(t *testing.T) {
+		fake := retryNode()
+		var nonces []int64
+		fake.ExecuteFunc = func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error) {
+			nonces = append(nonces, txOpts.Nonce)
+			if len(nonces) == 1 {
+				return nil, connRefused
+			}
+			return transactions.TxHash{1}, nil
+		}
+		_, err := transport.NewRetryClient(fake, policy, []byte{1}).Execute(ctx, "dbid", "insert_record", nil)
+		require.NoError(t, err, "Execute should succeed on the second attempt")
+		assert.Equal(t, []int64{8, 8}, nonces)
+	})
+
+	t.Run("AmbiguousBroadcastErrors", func(t *testing.T) {
+		// the transaction may have reached the node, so it's not sent again
+		fake := retryNode(errors.New("Gateway Timeout"))
+		_, err := transport.NewRetryClient(fake, policy, []byte{1}).Execute(ctx, "dbid", "insert_record", nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, fake.Requests("Execute"))
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		p := transport.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+		assert.Equal(t, 100*time.Millisecond, p.Backoff(1))
+		assert.Equal(t, 400*time.Millisecond, p.Backoff(3))
+		assert.Equal(t, time.Second, p.Backoff(10))
+	})
+}
@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/trufnetwork/sdk-go"
+
+// TelemetryOptions sets where spans and metrics go. Nil providers are replaced by the global ones
+type TelemetryOptions struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+}
+
+type streamIdKey struct{}
+
+// WithStreamId adds the stream id to the spans of the requests made under the context
+func WithStreamId(ctx context.Context, streamId string) context.Context {
+	return context.WithValue(ctx, streamIdKey{}, streamId)
+}
+
+// TelemetryClient creates a span for every request, and records its latency and errors.
+// Transaction waits are recorded in their own histogram
+type TelemetryClient struct {
+	kwilClientType.Client
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+	txWait   metric.Float64Histogram
+}
+
+var _ kwilClientType.Client = (*TelemetryClient)(nil)
+
+func NewTelemetryClient(inner kwilClientType.Client, options TelemetryOptions) (*TelemetryClient, error) {
+	tracerProvider := options.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	meterProvider := options.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	meter := meterProvider.Meter(instrumentationName)
+
+	t := &TelemetryClient{
+		Client: inner,
+		tracer: tracerProvider.Tracer(instrumentationName),
+	}
+
+	var err error
+	if t.requests, err = meter.Int64Counter("tn.client.requests",
+		metric.WithDescription("Requests made to the node")); err != nil {
+		return nil, err
+	}
+	if t.errors, err = meter.Int64Counter("tn.client.errors",
+		metric.WithDescription("Requests that failed, including transactions mined with a failed result")); err != nil {
+		return nil, err
+	}
+	if t.duration, err = meter.Float64Histogram("tn.client.request.duration",
+		metric.WithDescription("Duration of requests made to the node"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if t.txWait, err = meter.Float64Histogram("tn.client.tx.wait.duration",
+		metric.WithDescription("Time waited for transactions to be mined"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// observe runs fn inside a span, recording its duration and error
+func observe[T any](ctx context.Context, t *TelemetryClient, operation string, dbid string, procedure string, fn func(ctx context.Context) (T, error)) (T, error) {
+	metricAttrs := []attribute.KeyValue{attribute.String("tn.operation", operation)}
+	if procedure != "" {
+		metricAttrs = append(metricAttrs, attribute.String("tn.procedure", procedure))
+	}
+	spanAttrs := append([]attribute.KeyValue(nil), metricAttrs...)
+	if dbid != "" {
+		spanAttrs = append(spanAttrs, attribute.String("tn.dbid", dbid))
+	}
+	if streamId, ok := ctx.Value(streamIdKey{}).(string); ok {
+		spanAttrs = append(spanAttrs, attribute.String("tn.stream_id", streamId))
+	}
+
+	spanName := operation
+	if procedure != "" {
+		spanName += " " + procedure
+	}
+	ctx, span := t.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(spanAttrs...))
+	defer span.End()
+
+	start := time.Now()
+	result, err := fn(ctx)
+	elapsed := time.Since(start).Seconds()
+
+	attrs := metric.WithAttributes(metricAttrs...)
+	t.requests.Add(ctx, 1, attrs)
+	t.duration.Record(ctx, elapsed, attrs)
+	if err != nil {
+		t.errors.Add(ctx, 1, attrs)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return result, err
+}
+
+// ## View calls
+
+func (t *TelemetryClient) Call(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+	return observe(ctx, t, "Call", dbid, procedure, func(ctx context.Context) (*kwilClientType.Records, error) {
+		return t.Client.Call(ctx, dbid, procedure, inputs)
+	})
+}
+
+func (t *TelemetryClient) CallAction(ctx context.Context, dbid string, action string, inputs []any) (*kwilClientType.Records, error) {
+	return observe(ctx, t, "CallAction", dbid, action, func(ctx context.Context) (*kwilClientType.Records, error) {
+		return t.Client.CallAction(ctx, dbid, action, inputs)
+	})
+}
+
+func (t *TelemetryClient) Query(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+	return observe(ctx, t, "Query", dbid, "", func(ctx context.Context) (*kwilClientType.Records, error) {
+		return t.Client.Query(ctx, dbid, query)
+	})
+}
+
+func (t *TelemetryClient) GetSchema(ctx context.Context, dbid string) (*kwiltypes.Schema, error) {
+	return observe(ctx, t, "GetSchema", dbid, "", func(ctx context.Context) (*kwiltypes.Schema, error) {
+		return t.Client.GetSchema(ctx, dbid)
+	})
+}
+
+func (t *TelemetryClient) ListDatabases(ctx context.Context, owner []byte) ([]*kwiltypes.DatasetIdentifier, error) {
+	return observe(ctx, t, "ListDatabases", "", "", func(ctx context.Context) ([]*kwiltypes.DatasetIdentifier, error) {
+		return t.Client.ListDatabases(ctx, owner)
+	})
+}
+
+// WaitTx also records the wait in its own histogram, and counts transactions mined with a failed result as errors
+func (t *TelemetryClient) WaitTx(ctx context.Context, txHash []byte, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	start := time.Now()
+	res, err := observe(ctx, t, "WaitTx", "", "", func(ctx context.Context) (*transactions.TcTxQueryResponse, error) {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("tn.tx_hash", transactions.TxHash(txHash).Hex()))
+		return t.Client.WaitTx(ctx, txHash, interval)
+	})
+
+	failed := err != nil || transactions.TxCode(res.TxResult.Code) != transactions.CodeOk
+	t.txWait.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attribute.Bool("tn.tx_failed", failed)))
+	if err == nil && failed {
+		t.errors.Add(ctx, 1, metric.WithAttributes(attribute.String("tn.operation", "WaitTx")))
+	}
+
+	return res, err
+}
+
+// ## Broadcasts
+
+func (t *TelemetryClient) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return observe(ctx, t, "Execute", dbid, action, func(ctx context.Context) (transactions.TxHash, error) {
+		return t.Client.Execute(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (t *TelemetryClient) ExecuteAction(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return observe(ctx, t, "ExecuteAction", dbid, action, func(ctx context.Context) (transactions.TxHash, error) {
+		return t.Client.ExecuteAction(ctx, dbid, action, tuples, opts...)
+	})
+}
+
+func (t *TelemetryClient) DeployDatabase(ctx context.Context, payload *kwiltypes.Schema, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return observe(ctx, t, "DeployDatabase", "", "", func(ctx context.Context) (transactions.TxHash, error) {
+		return t.Client.DeployDatabase(ctx, payload, opts...)
+	})
+}
+
+func (t *TelemetryClient) DropDatabase(ctx context.Context, name string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return observe(ctx, t, "DropDatabase", "", "", func(ctx context.Context) (transactions.TxHash, error) {
+		return t.Client.DropDatabase(ctx, name, opts...)
+	})
+}
+
+func (t *TelemetryClient) DropDatabaseID(ctx context.Context, dbid string, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	return observe(ctx, t, "DropDatabaseID", dbid, "", func(ctx context.Context) (transactions.TxHash, error) {
+		return t.Client.DropDatabaseID(ctx, dbid, opts...)
+	})
+}
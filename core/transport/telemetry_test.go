@@ -0,0 +1,105 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/transport"
+	"github.com/trufnetwork/sdk-go/internal/kwiltest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTelemetry checks the spans and metrics recorded for requests, using in-memory exporters.
+func TestTelemetry(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// fails calls to the "fails" procedure, and mines every transaction with a failed result
+	node := &kwiltest.Client{
+		CallFunc: func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+			if procedure == "fails" {
+				return nil, errors.New("boom")
+			}
+			return kwilClientType.NewRecordsFromMaps([]map[string]any{{"value": true}}), nil
+		},
+		WaitTxFunc: func(ctx context.Context, txHash []byte) (*transactions.TcTxQueryResponse, error) {
+			return &transactions.TcTxQueryResponse{TxResult: transactions.TransactionResult{Code: transactions.CodeUnknownError.Uint32()}}, nil
+		},
+	}
+	client, err := transport.NewTelemetryClient(node, transport.TelemetryOptions{
+		TracerProvider: tracerProvider,
+		MeterProvider:  meterProvider,
+	})
+	require.NoError(t, err, "Failed to create telemetry client")
+
+	streamCtx := transport.WithStreamId(ctx, "st123")
+	_, err = client.GetSchema(streamCtx, "dbid")
+	require.NoError(t, err, "GetSchema should succeed")
+	spans.Reset()
+
+	_, err = client.Call(streamCtx, "dbid", "is_wallet_allowed_to_read", nil)
+	require.NoError(t, err, "Call should succeed")
+	_, err = client.Call(ctx, "dbid", "fails", nil)
+	assert.Error(t, err)
+	_, err = client.WaitTx(ctx, transactions.TxHash{1}, time.Millisecond)
+	require.NoError(t, err, "WaitTx should succeed")
+
+	t.Run("Spans", func(t *testing.T) {
+		recorded := spans.GetSpans()
+		if !assert.Equal(t, 3, len(recorded)) {
+			return
+		}
+
+		canRead := recorded[0]
+		assert.Equal(t, "Call is_wallet_allowed_to_read", canRead.Name)
+		assert.Contains(t, canRead.Attributes, attribute.String("tn.dbid", "dbid"))
+		assert.Contains(t, canRead.Attributes, attribute.String("tn.procedure", "is_wallet_allowed_to_read"))
+		assert.Contains(t, canRead.Attributes, attribute.String("tn.stream_id", "st123"))
+
+		assert.Equal(t, codes.Error, recorded[1].Status.Code)
+		assert.Equal(t, "WaitTx", recorded[2].Name)
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		var data metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(ctx, &data), "Failed to collect metrics")
+
+		sums := map[string]int64{}
+		histograms := map[string]uint64{}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				switch d := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					for _, point := range d.DataPoints {
+						sums[m.Name] += point.Value
+					}
+				case metricdata.Histogram[float64]:
+					for _, point := range d.DataPoints {
+						histograms[m.Name] += point.Count
+					}
+				}
+			}
+		}
+
+		// GetSchema, the call, the failed call and WaitTx
+		assert.Equal(t, int64(4), sums["tn.client.requests"])
+		// the failed call, and the transaction mined with a failed result
+		assert.Equal(t, int64(2), sums["tn.client.errors"])
+		assert.Equal(t, uint64(4), histograms["tn.client.request.duration"])
+		assert.Equal(t, uint64(1), histograms["tn.client.tx.wait.duration"])
+	})
+}
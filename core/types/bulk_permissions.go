@@ -0,0 +1,63 @@
+package types
+
+import (
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+type StreamPermission string
+
+const (
+	// ReadPermission is granted to wallets, through the `allow_read_wallet` metadata
+	ReadPermission StreamPermission = "read"
+	// WritePermission is granted to wallets, through the `allow_write_wallet` metadata
+	WritePermission StreamPermission = "write"
+	// ComposePermission is granted to streams, through the `allow_compose_stream` metadata
+	ComposePermission StreamPermission = "compose"
+)
+
+// StreamSelector selects the streams of a bulk operation. The selected streams are merged and deduplicated
+type StreamSelector struct {
+	// Streams is an explicit list of streams
+	Streams []StreamLocator
+	// Owner optional. Selects every initialized stream deployed by this wallet
+	Owner *util.EthereumAddress
+	// TaxonomyRoot optional. Selects the stream and every stream below it in the taxonomy
+	TaxonomyRoot *StreamLocator
+}
+
+type BulkPermissionParams struct {
+	Permission StreamPermission
+	// Wallet is the grantee of read and write permissions
+	Wallet util.EthereumAddress
+	// ComposingStream is the grantee of compose permissions
+	ComposingStream StreamLocator
+	Streams         StreamSelector
+	// Concurrency is the maximum number of streams processed at once. Defaults to 5
+	Concurrency int
+}
+
+// BulkPermissionResult is the outcome of a bulk operation for a single stream
+type BulkPermissionResult struct {
+	Stream StreamLocator
+	// TxHash is empty if no transaction was needed or it couldn't be sent
+	TxHash transactions.TxHash
+	// Skipped is true if the stream was already in the desired state
+	Skipped bool
+	// Err is nil if the transaction was mined successfully or skipped
+	Err error
+}
+
+// BulkPermissionResults are ordered as the streams were selected
+type BulkPermissionResults []BulkPermissionResult
+
+// Failed returns the results with errors
+func (r BulkPermissionResults) Failed() BulkPermissionResults {
+	var failed BulkPermissionResults
+	for _, result := range r {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
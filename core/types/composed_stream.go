@@ -24,13 +24,119 @@ type DescribeTaxonomiesParams struct {
 	LatestVersion bool
 }
 
+type AddChildParams struct {
+	// Child is the stream to be added to the taxonomy, with its weight
+	Child TaxonomyItem
+	// StartDate optional. Date from which the new version is valid, defaults to the one of the previous version
+	StartDate *civil.Date
+}
+
+type RemoveChildParams struct {
+	// Child is the stream to be removed from the taxonomy
+	Child StreamLocator
+	// StartDate optional. Date from which the new version is valid, defaults to the one of the previous version
+	StartDate *civil.Date
+}
+
+type UpdateWeightsParams struct {
+	// Weights are the new weights of existing children. Children not listed keep their current weight
+	Weights []TaxonomyItem
+	// StartDate Date from which the new weights are used. Must be after the start date of the previous version
+	StartDate *civil.Date
+}
+
+type SetTaxonomyOptions struct {
+	// GrantCompose if true, children with private compose visibility owned by the stream owner
+	// are allowed to be composed by the stream before the taxonomy is set
+	GrantCompose bool
+	// GrantResults optional. Receives the outcome of the grants
+	GrantResults *ComposeGrantResults
+}
+
+type SetTaxonomyOption func(*SetTaxonomyOptions)
+
+// WithComposeGrants allows the stream to compose its private children, if they have the same owner.
+// A warning is logged for private children of other owners
+func WithComposeGrants() SetTaxonomyOption {
+	return func(o *SetTaxonomyOptions) {
+		o.GrantCompose = true
+	}
+}
+
+// WithComposeGrantResults is WithComposeGrants, storing the outcome of every child in results,
+// so the grant transactions can be waited for
+func WithComposeGrantResults(results *ComposeGrantResults) SetTaxonomyOption {
+	return func(o *SetTaxonomyOptions) {
+		o.GrantCompose = true
+		o.GrantResults = results
+	}
+}
+
+// ComposeGrantResult is the outcome of allowing a stream to compose one of its children
+type ComposeGrantResult struct {
+	Child StreamLocator
+	// TxHash is empty if no grant was needed or possible
+	TxHash transactions.TxHash
+	// NotOwned is true if the child has private compose visibility and isn't owned by the signer, so it
+	// stays unreadable through the stream until its owner allows it
+	NotOwned bool
+}
+
+// ComposeGrantResults are ordered as the children of the taxonomy, without duplicates
+type ComposeGrantResults []ComposeGrantResult
+
+// TxHashes returns the hashes of the grants that were sent
+func (r ComposeGrantResults) TxHashes() []transactions.TxHash {
+	var txHashes []transactions.TxHash
+	for _, result := range r {
+		if result.TxHash != nil {
+			txHashes = append(txHashes, result.TxHash)
+		}
+	}
+	return txHashes
+}
+
+// NotOwned returns the children that need a grant from another owner
+func (r ComposeGrantResults) NotOwned() []StreamLocator {
+	var children []StreamLocator
+	for _, result := range r {
+		if result.NotOwned {
+			children = append(children, result.Child)
+		}
+	}
+	return children
+}
+
+// TaxonomyEditResult is the outcome of an incremental taxonomy edit
+type TaxonomyEditResult struct {
+	TxHash transactions.TxHash
+	// Previous is the latest version before the edit
+	Previous Taxonomy
+	// Next is the version that was published
+	Next Taxonomy
+	Diff TaxonomyDiff
+}
+
 type IComposedStream interface {
 	// IStream methods are also available in IPrimitiveStream
 	IStream
 	// DescribeTaxonomies returns the taxonomy of the stream
 	DescribeTaxonomies(ctx context.Context, params DescribeTaxonomiesParams) (Taxonomy, error)
 	// SetTaxonomy sets the taxonomy of the stream
-	SetTaxonomy(ctx context.Context, taxonomies Taxonomy) (transactions.TxHash, error)
+	SetTaxonomy(ctx context.Context, taxonomies Taxonomy, opts ...SetTaxonomyOption) (transactions.TxHash, error)
+	// GrantComposeToPrivateChildren allows the stream to compose the children that have private compose
+	// visibility and are owned by the signer of the client. Children of other owners are reported as NotOwned
+	GrantComposeToPrivateChildren(ctx context.Context, taxonomies Taxonomy) (ComposeGrantResults, error)
+	// AddChild publishes a new taxonomy version with the latest children plus the given one
+	AddChild(ctx context.Context, params AddChildParams) (TaxonomyEditResult, error)
+	// RemoveChild publishes a new taxonomy version with the latest children except the given one
+	RemoveChild(ctx context.Context, params RemoveChildParams) (TaxonomyEditResult, error)
+	// UpdateWeights publishes a new taxonomy version with the latest children, reweighted
+	UpdateWeights(ctx context.Context, params UpdateWeightsParams) (TaxonomyEditResult, error)
+	// GetEffectiveTaxonomy returns the weights the stream uses on the given date
+	GetEffectiveTaxonomy(ctx context.Context, date civil.Date) (EffectiveTaxonomy, error)
+	// DescribeTaxonomyTimeline returns the weights the stream uses from every start-dated change on
+	DescribeTaxonomyTimeline(ctx context.Context) ([]TaxonomyTimelineEntry, error)
 }
 
 // MarshalJSON Custom marshaler for TaxonomyDefinition
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Errors raised by the stream contracts. They are matched with errors.Is,
+// both on call errors and on *TxFailedError
+var (
+	ErrorWalletNotAllowedToRead     = errors.New("wallet not allowed to read")
+	ErrorWalletNotAllowedToWrite    = errors.New("wallet not allowed to write")
+	ErrorStreamOwnerOnly            = errors.New("stream owner only procedure")
+	ErrorContractNotInitialized     = errors.New("contract must be initiated")
+	ErrorContractAlreadyInitialized = errors.New("this contract was already initialized")
+	ErrorBaseValueZero              = errors.New("base value is 0")
+	ErrorStreamNotAllowedToCompose  = errors.New("stream not allowed to compose")
+)
+
+var contractErrors = []error{
+	ErrorWalletNotAllowedToRead,
+	ErrorWalletNotAllowedToWrite,
+	ErrorStreamOwnerOnly,
+	ErrorContractNotInitialized,
+	ErrorContractAlreadyInitialized,
+	ErrorBaseValueZero,
+	ErrorStreamNotAllowedToCompose,
+}
+
+// ContractErrorFromMessage returns the sentinel error of a contract error message, nil if unknown.
+// Matching is case-insensitive, as contracts differ in capitalization
+func ContractErrorFromMessage(message string) error {
+	message = strings.ToLower(message)
+	for _, contractErr := range contractErrors {
+		if strings.Contains(message, contractErr.Error()) {
+			return contractErr
+		}
+	}
+	return nil
+}
+
+// contractError keeps the original error, while matching its sentinel with errors.Is
+type contractError struct {
+	sentinel error
+	cause    error
+}
+
+func (e *contractError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *contractError) Unwrap() []error {
+	return []error{e.sentinel, e.cause}
+}
+
+// MapContractError makes a known contract error match its sentinel. Other errors are returned as they are
+func MapContractError(err error) error {
+	if err == nil {
+		return nil
+	}
+	sentinel := ContractErrorFromMessage(err.Error())
+	if sentinel == nil {
+		return err
+	}
+	return &contractError{sentinel: sentinel, cause: err}
+}
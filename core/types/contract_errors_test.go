@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// TestContractErrors checks that contract error messages match their sentinel errors.
+func TestContractErrors(t *testing.T) {
+	for message, sentinel := range map[string]error{
+		"ERROR: wallet not allowed to read (SQLSTATE P0001)":            types.ErrorWalletNotAllowedToRead,
+		"ERROR: wallet not allowed to write (SQLSTATE P0001)":           types.ErrorWalletNotAllowedToWrite,
+		"ERROR: Stream owner only procedure (SQLSTATE P0001)":           types.ErrorStreamOwnerOnly,
+		"ERROR: contract must be initiated (SQLSTATE P0001)":            types.ErrorContractNotInitialized,
+		"ERROR: this contract was already initialized (SQLSTATE P0001)": types.ErrorContractAlreadyInitialized,
+		"ERROR: base value is 0 (SQLSTATE P0001)":                       types.ErrorBaseValueZero,
+		"ERROR: Stream not allowed to compose (SQLSTATE P0001)":         types.ErrorStreamNotAllowedToCompose,
+		"ERROR: stream not allowed to compose (SQLSTATE P0001)":         types.ErrorStreamNotAllowedToCompose,
+	} {
+		// from call errors
+		err := errors.WithStack(types.MapContractError(errors.New("err code = -300, msg = " + message)))
+		assert.True(t, errors.Is(err, sentinel), message)
+		assert.Contains(t, err.Error(), "err code = -300", "the original message should be kept")
+
+		// from transaction results
+		err = errors.WithStack(types.NewTxFailedError(transactions.TxHash{1}, transactions.TransactionResult{
+			Code: uint32(transactions.CodeUnknownError),
+			Log:  message,
+		}))
+		assert.True(t, errors.Is(err, sentinel), message)
+	}
+
+	unknown := errors.New("something else")
+	assert.Equal(t, unknown, types.MapContractError(unknown))
+	assert.Nil(t, types.MapContractError(nil))
+	assert.False(t, errors.Is(types.NewTxFailedError(transactions.TxHash{1}, transactions.TransactionResult{Log: "other"}), types.ErrorBaseValueZero))
+}
@@ -2,6 +2,9 @@ package types
 
 import (
 	"fmt"
+	"strconv"
+
+	"github.com/cockroachdb/apd/v3"
 	"github.com/pkg/errors"
 )
 
@@ -22,30 +25,79 @@ const (
 	ReadVisibilityKey     MetadataKey = "read_visibility"
 	AllowReadWalletKey    MetadataKey = "allow_read_wallet"
 	AllowComposeStreamKey MetadataKey = "allow_compose_stream"
+	AllowWriteWalletKey   MetadataKey = "allow_write_wallet"
 	DefaultBaseDateKey    MetadataKey = "default_base_date"
+	DisplayNameKey        MetadataKey = "display_name"
+	DescriptionKey        MetadataKey = "description"
+	UnitKey               MetadataKey = "unit"
+	FrequencyKey          MetadataKey = "frequency"
+	SourceURLKey          MetadataKey = "source_url"
+	MethodologyVersionKey MetadataKey = "methodology_version"
 )
 
+// metadataKeyTypes are the types of the keys used by the contracts and the SDK
+var metadataKeyTypes = map[MetadataKey]MetadataType{
+	ReadonlyKey:           MetadataTypeString,
+	StreamOwner:           MetadataTypeRef,
+	TypeKey:               MetadataTypeString,
+	ComposeVisibilityKey:  MetadataTypeInt,
+	ReadVisibilityKey:     MetadataTypeInt,
+	AllowReadWalletKey:    MetadataTypeRef,
+	AllowComposeStreamKey: MetadataTypeRef,
+	AllowWriteWalletKey:   MetadataTypeRef,
+	DefaultBaseDateKey:    MetadataTypeString,
+	DisplayNameKey:        MetadataTypeString,
+	DescriptionKey:        MetadataTypeString,
+	UnitKey:               MetadataTypeString,
+	FrequencyKey:          MetadataTypeString,
+	SourceURLKey:          MetadataTypeString,
+	MethodologyVersionKey: MetadataTypeString,
+}
+
+// GetType returns the type of a well-known key. Custom keys default to string,
+// use TypeForValue and TypeForStored to get the type a custom key is written and read with
 func (s MetadataKey) GetType() MetadataType {
-	switch s {
-	case ReadonlyKey:
-		return MetadataTypeString
-	case StreamOwner:
-		return MetadataTypeRef
-	case TypeKey:
-		return MetadataTypeString
-	case ComposeVisibilityKey:
-		return MetadataTypeInt
-	case ReadVisibilityKey:
-		return MetadataTypeInt
-	case AllowReadWalletKey:
-		return MetadataTypeRef
-	case AllowComposeStreamKey:
-		return MetadataTypeRef
-	case DefaultBaseDateKey:
-		return MetadataTypeString
-	default:
-		return MetadataTypeString
+	if metadataType, ok := metadataKeyTypes[s]; ok {
+		return metadataType
 	}
+	return MetadataTypeString
+}
+
+// IsWellKnown returns true if the key is used by the contracts or the SDK, and so has a fixed type
+func (s MetadataKey) IsWellKnown() bool {
+	_, ok := metadataKeyTypes[s]
+	return ok
+}
+
+// TypeForValue returns the type a value is stored with under this key.
+// Well-known keys keep their type, custom keys take the type of the value
+func (s MetadataKey) TypeForValue(value MetadataValue) (MetadataType, error) {
+	if value.valueType == "" {
+		return "", errors.New(fmt.Sprintf("empty metadata value for key %s", s))
+	}
+
+	if !s.IsWellKnown() {
+		return value.valueType, nil
+	}
+
+	keyType := s.GetType()
+	// strings and refs are both text, refs are only lowercased and indexed
+	textTypes := (keyType == MetadataTypeRef || keyType == MetadataTypeString) &&
+		(value.valueType == MetadataTypeRef || value.valueType == MetadataTypeString)
+	if keyType != value.valueType && !textTypes {
+		return "", errors.New(fmt.Sprintf("key %s expects a %s value, got %s", s, keyType, value.valueType))
+	}
+
+	return keyType, nil
+}
+
+// TypeForStored returns the type a row read under this key has.
+// Well-known keys keep their type, custom keys keep the type they were stored with
+func (s MetadataKey) TypeForStored(stored MetadataType) MetadataType {
+	if s.IsWellKnown() {
+		return s.GetType()
+	}
+	return stored
 }
 
 func (s MetadataKey) String() string {
@@ -63,29 +115,127 @@ const (
 )
 
 func (s MetadataType) StringFromValue(valueObj MetadataValue) (string, error) {
-	value := valueObj.value
-
 	switch s {
 	case MetadataTypeInt:
-		return fmt.Sprintf("%d", value.(int)), nil
+		value, err := valueObj.AsInt()
+		return strconv.Itoa(value), err
 	case MetadataTypeBool:
-		return fmt.Sprintf("%t", value.(bool)), nil
+		value, err := valueObj.AsBool()
+		return strconv.FormatBool(value), err
 	case MetadataTypeFloat:
-		return fmt.Sprintf("%f", value.(float64)), nil
+		value, err := valueObj.AsDecimal()
+		if err != nil {
+			return "", err
+		}
+		return value.Text('f'), nil
+	case MetadataTypeString, MetadataTypeRef:
+		return valueObj.AsString()
+	default:
+		return "", errors.New(fmt.Sprintf("unknown metadata type: %s", s))
+	}
+}
+
+// ParseMetadataValue parses a value in the format it's inserted with, i.e. from a MetadataRecord
+func ParseMetadataValue(metadataType MetadataType, value string) (MetadataValue, error) {
+	switch metadataType {
+	case MetadataTypeInt:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return MetadataValue{}, errors.WithStack(err)
+		}
+		return NewMetadataValue(i), nil
+	case MetadataTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return MetadataValue{}, errors.WithStack(err)
+		}
+		return NewMetadataValue(b), nil
+	case MetadataTypeFloat:
+		d, _, err := apd.NewFromString(value)
+		if err != nil {
+			return MetadataValue{}, errors.WithStack(err)
+		}
+		return NewDecimalMetadataValue(*d), nil
 	case MetadataTypeString:
-		return value.(string), nil
+		return NewMetadataValue(value), nil
 	case MetadataTypeRef:
-		return value.(string), nil
+		return NewRefMetadataValue(value), nil
 	default:
-		return "", errors.New(fmt.Sprintf("unknown metadata type: %s", s))
+		return MetadataValue{}, errors.New(fmt.Sprintf("unknown metadata type: %s", metadataType))
 	}
 }
 
 type MetadataValue struct {
 	// do not export this, to prevent direct access to the value
-	value any
+	value     any
+	valueType MetadataType
 }
 
+// NewMetadataValue creates a value typed after its Go type. Strings are plain strings, use NewRefMetadataValue for refs
 func NewMetadataValue[T string | int | bool | float64 | MetadataValue](value T) MetadataValue {
-	return MetadataValue{value: value}
+	switch v := any(value).(type) {
+	case MetadataValue:
+		return v
+	case int:
+		return MetadataValue{value: v, valueType: MetadataTypeInt}
+	case bool:
+		return MetadataValue{value: v, valueType: MetadataTypeBool}
+	case float64:
+		return MetadataValue{value: v, valueType: MetadataTypeFloat}
+	default:
+		return MetadataValue{value: v, valueType: MetadataTypeString}
+	}
+}
+
+// NewRefMetadataValue creates a ref value, i.e. a wallet address or a DBID. Refs are stored lowercased and indexed
+func NewRefMetadataValue(ref string) MetadataValue {
+	return MetadataValue{value: ref, valueType: MetadataTypeRef}
+}
+
+// NewDecimalMetadataValue creates a decimal value, stored as decimal(36,18)
+func NewDecimalMetadataValue(value apd.Decimal) MetadataValue {
+	return MetadataValue{value: value, valueType: MetadataTypeFloat}
+}
+
+// Type returns the type the value was created with
+func (v MetadataValue) Type() MetadataType {
+	return v.valueType
+}
+
+func (v MetadataValue) AsInt() (int, error) {
+	value, ok := v.value.(int)
+	if !ok {
+		return 0, errors.New(fmt.Sprintf("metadata value is %s, not int", v.valueType))
+	}
+	return value, nil
+}
+
+func (v MetadataValue) AsBool() (bool, error) {
+	value, ok := v.value.(bool)
+	if !ok {
+		return false, errors.New(fmt.Sprintf("metadata value is %s, not bool", v.valueType))
+	}
+	return value, nil
+}
+
+// AsDecimal returns decimal values, and float64 values converted to decimal
+func (v MetadataValue) AsDecimal() (*apd.Decimal, error) {
+	switch value := v.value.(type) {
+	case apd.Decimal:
+		return &value, nil
+	case float64:
+		d, err := new(apd.Decimal).SetFloat64(value)
+		return d, errors.WithStack(err)
+	default:
+		return nil, errors.New(fmt.Sprintf("metadata value is %s, not float", v.valueType))
+	}
+}
+
+// AsString returns string and ref values
+func (v MetadataValue) AsString() (string, error) {
+	value, ok := v.value.(string)
+	if !ok {
+		return "", errors.New(fmt.Sprintf("metadata value is %s, not string", v.valueType))
+	}
+	return value, nil
 }
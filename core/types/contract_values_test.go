@@ -0,0 +1,85 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// TestMetadataValues checks how metadata values are typed and formatted.
+func TestMetadataValues(t *testing.T) {
+	customKey := types.MetadataKey("custom_key")
+
+	t.Run("CustomKeysTakeTheValueType", func(t *testing.T) {
+		decimal, _, err := apd.NewFromString("1.25")
+		require.NoError(t, err, "Failed to parse decimal")
+
+		for _, tc := range []struct {
+			value        types.MetadataValue
+			expectedType types.MetadataType
+			expected     string
+		}{
+			{types.NewMetadataValue(42), types.MetadataTypeInt, "42"},
+			{types.NewMetadataValue(true), types.MetadataTypeBool, "true"},
+			{types.NewMetadataValue(0.5), types.MetadataTypeFloat, "0.5"},
+			{types.NewDecimalMetadataValue(*decimal), types.MetadataTypeFloat, "1.25"},
+			{types.NewMetadataValue("hello"), types.MetadataTypeString, "hello"},
+			{types.NewRefMetadataValue("0xabc"), types.MetadataTypeRef, "0xabc"},
+		} {
+			metadataType, err := customKey.TypeForValue(tc.value)
+			require.NoError(t, err, "Failed to get type")
+			assert.Equal(t, tc.expectedType, metadataType)
+
+			str, err := metadataType.StringFromValue(tc.value)
+			require.NoError(t, err, "Failed to format value")
+			assert.Equal(t, tc.expected, str)
+
+			parsed, err := types.ParseMetadataValue(metadataType, str)
+			require.NoError(t, err, "Failed to parse value")
+			assert.Equal(t, tc.expectedType, parsed.Type())
+		}
+	})
+
+	t.Run("WellKnownKeysKeepTheirType", func(t *testing.T) {
+		metadataType, err := types.AllowReadWalletKey.TypeForValue(types.NewMetadataValue("0xabc"))
+		require.NoError(t, err, "Failed to get type")
+		assert.Equal(t, types.MetadataTypeRef, metadataType)
+
+		_, err = types.ReadVisibilityKey.TypeForValue(types.NewMetadataValue("public"))
+		assert.Error(t, err, "a string is not a valid visibility")
+	})
+
+	t.Run("ReadsUseTheWriteTypes", func(t *testing.T) {
+		for _, value := range []types.MetadataValue{
+			types.NewMetadataValue(42),
+			types.NewMetadataValue(true),
+			types.NewMetadataValue(0.5),
+		} {
+			metadataType, err := customKey.TypeForValue(value)
+			require.NoError(t, err, "Failed to get type")
+			assert.Equal(t, metadataType, customKey.TypeForStored(metadataType))
+		}
+
+		assert.Equal(t, types.MetadataTypeRef, types.AllowReadWalletKey.TypeForStored(types.MetadataTypeString))
+	})
+
+	t.Run("Accessors", func(t *testing.T) {
+		value := types.NewMetadataValue(7)
+		i, err := value.AsInt()
+		require.NoError(t, err, "Failed to get int")
+		assert.Equal(t, 7, i)
+
+		_, err = value.AsBool()
+		assert.Error(t, err)
+
+		record := types.MetadataRecord{Key: customKey, Type: types.MetadataTypeFloat, Value: "2.500000000000000000"}
+		typed, err := record.TypedValue()
+		require.NoError(t, err, "Failed to parse record value")
+		d, err := typed.AsDecimal()
+		require.NoError(t, err, "Failed to get decimal")
+		assert.Equal(t, "2.500000000000000000", d.String())
+	})
+}
@@ -0,0 +1,200 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/golang-sql/civil"
+)
+
+// TaxonomyRecord is a single row of a composed stream taxonomy, as stored by the contract
+type TaxonomyRecord struct {
+	ChildStream StreamLocator
+	Weight      float64
+	// CreatedAt block height
+	CreatedAt int
+	// DisabledAt block height, nil if the row is enabled
+	DisabledAt *int
+	Version    int
+	// StartDate nil if the row was set without a start date
+	StartDate *civil.Date
+}
+
+// EffectiveTaxonomyItem is the weight a child has on a given date, and the row it comes from
+type EffectiveTaxonomyItem struct {
+	ChildStream StreamLocator
+	Weight      float64
+	// StartDate of the row the weight comes from, nil if it has none
+	StartDate *civil.Date
+	// Version of the row the weight comes from
+	Version int
+}
+
+// EffectiveTaxonomy are the weights used by the composed stream on Date
+type EffectiveTaxonomy struct {
+	Date  civil.Date
+	Items []EffectiveTaxonomyItem
+}
+
+// TaxonomyTimelineEntry are the weights used from a start date until the next entry
+type TaxonomyTimelineEntry struct {
+	// From nil means the weights apply before any start-dated change
+	From  *civil.Date
+	Items []EffectiveTaxonomyItem
+}
+
+// EffectiveTaxonomyAt reproduces how the composed stream contract picks weights for a date:
+// - children are the ones from the latest enabled version
+// - for each child, the row with the closest start date at or before the date is used, rows without start date
+// are used only if there's no dated row before
+// - if there's none, the row with the earliest start date is used
+//
+// As in the contract, rows are matched by child stream id only and disabled rows are not excluded.
+// Ties on start date, which the contract leaves undefined, are broken by the latest version.
+func EffectiveTaxonomyAt(records []TaxonomyRecord, date civil.Date) EffectiveTaxonomy {
+	effective := EffectiveTaxonomy{Date: date}
+
+	for _, child := range latestTaxonomyChildren(records) {
+		var candidates, all []TaxonomyRecord
+		for _, record := range records {
+			if record.ChildStream.StreamId.String() != child.StreamId.String() {
+				continue
+			}
+			all = append(all, record)
+			if record.StartDate == nil || !date.Before(*record.StartDate) {
+				candidates = append(candidates, record)
+			}
+		}
+
+		var selected TaxonomyRecord
+		if len(candidates) > 0 {
+			selected = closestTaxonomyRecord(candidates)
+		} else if len(all) > 0 {
+			selected = earliestTaxonomyRecord(all)
+		} else {
+			continue
+		}
+
+		effective.Items = append(effective.Items, EffectiveTaxonomyItem{
+			ChildStream: child,
+			Weight:      selected.Weight,
+			StartDate:   selected.StartDate,
+			Version:     selected.Version,
+		})
+	}
+
+	return effective
+}
+
+// TaxonomyTimeline lists the weights of the composed stream for every start date where they change
+func TaxonomyTimeline(records []TaxonomyRecord) []TaxonomyTimelineEntry {
+	// zero date sorts before any valid date, so only rows without start date are candidates
+	timeline := []TaxonomyTimelineEntry{
+		{Items: EffectiveTaxonomyAt(records, civil.Date{}).Items},
+	}
+
+	for _, startDate := range taxonomyStartDates(records) {
+		items := EffectiveTaxonomyAt(records, startDate).Items
+		if sameEffectiveWeights(timeline[len(timeline)-1].Items, items) {
+			continue
+		}
+		from := startDate
+		timeline = append(timeline, TaxonomyTimelineEntry{From: &from, Items: items})
+	}
+
+	return timeline
+}
+
+// latestTaxonomyChildren returns the children of the latest enabled version
+func latestTaxonomyChildren(records []TaxonomyRecord) []StreamLocator {
+	latestVersion := 0
+	for _, record := range records {
+		if record.DisabledAt == nil && record.Version > latestVersion {
+			latestVersion = record.Version
+		}
+	}
+
+	var children []StreamLocator
+	for _, record := range records {
+		if record.DisabledAt == nil && record.Version == latestVersion {
+			children = append(children, record.ChildStream)
+		}
+	}
+	return children
+}
+
+// closestTaxonomyRecord returns the record with the latest start date, rows without start date sort last
+func closestTaxonomyRecord(records []TaxonomyRecord) TaxonomyRecord {
+	sorted := sortedByStartDate(records)
+	return sorted[len(sorted)-1]
+}
+
+// earliestTaxonomyRecord returns the record with the earliest start date, rows without start date sort first
+func earliestTaxonomyRecord(records []TaxonomyRecord) TaxonomyRecord {
+	sorted := sortedByStartDate(records)
+	first := sorted[0]
+	// on ties, prefer the latest version as well
+	for _, record := range sorted[1:] {
+		if compareStartDates(record.StartDate, first.StartDate) != 0 {
+			break
+		}
+		first = record
+	}
+	return first
+}
+
+// sortedByStartDate sorts ascending by start date, then by version
+func sortedByStartDate(records []TaxonomyRecord) []TaxonomyRecord {
+	sorted := make([]TaxonomyRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if c := compareStartDates(sorted[i].StartDate, sorted[j].StartDate); c != 0 {
+			return c < 0
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+func compareStartDates(a, b *civil.Date) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case a.Before(*b):
+		return -1
+	case b.Before(*a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// taxonomyStartDates returns the distinct start dates of the records, ascending
+func taxonomyStartDates(records []TaxonomyRecord) []civil.Date {
+	seen := make(map[civil.Date]bool)
+	var dates []civil.Date
+	for _, record := range records {
+		if record.StartDate == nil || seen[*record.StartDate] {
+			continue
+		}
+		seen[*record.StartDate] = true
+		dates = append(dates, *record.StartDate)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+func sameEffectiveWeights(a, b []EffectiveTaxonomyItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].ChildStream.Equals(b[i].ChildStream) || a[i].Weight != b[i].Weight {
+			return false
+		}
+	}
+	return true
+}
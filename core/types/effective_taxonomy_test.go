@@ -0,0 +1,72 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// TestEffectiveTaxonomy checks that weights are picked with the same rules as the composed stream contract.
+func TestEffectiveTaxonomy(t *testing.T) {
+	dataProvider := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000001")
+	childA := types.StreamLocator{StreamId: util.GenerateStreamId("effective-taxonomy-a"), DataProvider: dataProvider}
+	childB := types.StreamLocator{StreamId: util.GenerateStreamId("effective-taxonomy-b"), DataProvider: dataProvider}
+
+	// version 1: A=1, B=1 without start date
+	// version 2: A=2, B=1 from 2020-06-01
+	// version 3: A=3 from 2021-01-01, B removed
+	records := []types.TaxonomyRecord{
+		{ChildStream: childA, Weight: 1, Version: 1},
+		{ChildStream: childB, Weight: 1, Version: 1},
+		{ChildStream: childA, Weight: 2, Version: 2, StartDate: unsafeParseDate("2020-06-01")},
+		{ChildStream: childB, Weight: 1, Version: 2, StartDate: unsafeParseDate("2020-06-01")},
+		{ChildStream: childA, Weight: 3, Version: 3, StartDate: unsafeParseDate("2021-01-01")},
+	}
+
+	t.Run("BeforeAnyStartDate", func(t *testing.T) {
+		effective := types.EffectiveTaxonomyAt(records, *unsafeParseDate("2020-01-01"))
+		// children come from the latest version only
+		if assert.Equal(t, 1, len(effective.Items)) {
+			assert.True(t, effective.Items[0].ChildStream.Equals(childA))
+			assert.Equal(t, 1.0, effective.Items[0].Weight)
+			assert.Nil(t, effective.Items[0].StartDate)
+		}
+	})
+
+	t.Run("ClosestStartDate", func(t *testing.T) {
+		effective := types.EffectiveTaxonomyAt(records, *unsafeParseDate("2020-12-31"))
+		if assert.Equal(t, 1, len(effective.Items)) {
+			assert.Equal(t, 2.0, effective.Items[0].Weight)
+			assert.Equal(t, 2, effective.Items[0].Version)
+		}
+
+		effective = types.EffectiveTaxonomyAt(records, *unsafeParseDate("2021-01-01"))
+		if assert.Equal(t, 1, len(effective.Items)) {
+			assert.Equal(t, 3.0, effective.Items[0].Weight)
+		}
+	})
+
+	t.Run("FallbackToEarliest", func(t *testing.T) {
+		dated := []types.TaxonomyRecord{
+			{ChildStream: childA, Weight: 5, Version: 1, StartDate: unsafeParseDate("2020-06-01")},
+			{ChildStream: childA, Weight: 7, Version: 2, StartDate: unsafeParseDate("2021-06-01")},
+		}
+		effective := types.EffectiveTaxonomyAt(dated, *unsafeParseDate("2019-01-01"))
+		if assert.Equal(t, 1, len(effective.Items)) {
+			assert.Equal(t, 5.0, effective.Items[0].Weight)
+		}
+	})
+
+	t.Run("Timeline", func(t *testing.T) {
+		timeline := types.TaxonomyTimeline(records)
+		if assert.Equal(t, 3, len(timeline)) {
+			assert.Nil(t, timeline[0].From)
+			assert.Equal(t, "2020-06-01", timeline[1].From.String())
+			assert.Equal(t, 2.0, timeline[1].Items[0].Weight)
+			assert.Equal(t, "2021-01-01", timeline[2].From.String())
+			assert.Equal(t, 3.0, timeline[2].Items[0].Weight)
+		}
+	})
+}
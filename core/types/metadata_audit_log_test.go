@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// TestMetadataAuditLogExport checks the CSV and JSON exports of the metadata history.
+func TestMetadataAuditLogExport(t *testing.T) {
+	disabledAt := 12
+	auditLog := types.MetadataAuditLog{
+		{RowId: "row-1", Key: types.ReadVisibilityKey, Type: types.MetadataTypeInt, Value: "1", CreatedAt: 5},
+		{RowId: "row-2", Key: types.AllowReadWalletKey, Type: types.MetadataTypeRef, Value: "0x1111111111111111111111111111111111111111", CreatedAt: 6, DisabledAt: &disabledAt},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, auditLog.WriteCSV(&buf), "Failed to write CSV")
+		assert.Equal(t, "row_id,key,type,value,created_at,disabled_at\n"+
+			"row-1,read_visibility,int,1,5,\n"+
+			"row-2,allow_read_wallet,ref,0x1111111111111111111111111111111111111111,6,12\n", buf.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, auditLog.WriteJSON(&buf), "Failed to write JSON")
+		assert.JSONEq(t, `[
+			{"row_id":"row-1","key":"read_visibility","type":"int","value":"1","created_at":5,"disabled_at":null},
+			{"row_id":"row-2","key":"allow_read_wallet","type":"ref","value":"0x1111111111111111111111111111111111111111","created_at":6,"disabled_at":12}
+		]`, buf.String())
+
+		buf.Reset()
+		require.NoError(t, types.MetadataAuditLog(nil).WriteJSON(&buf), "Failed to write JSON")
+		assert.JSONEq(t, `[]`, buf.String())
+	})
+}
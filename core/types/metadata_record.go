@@ -0,0 +1,100 @@
+package types
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// MetadataRecord is a metadata row of a stream, including disabled ones
+type MetadataRecord struct {
+	RowId string       `json:"row_id"`
+	Key   MetadataKey  `json:"key"`
+	Type  MetadataType `json:"type"`
+	// Value is the value as a string, in the same format used to insert it
+	Value string `json:"value"`
+	// CreatedAt block height
+	CreatedAt int `json:"created_at"`
+	// DisabledAt block height, nil if the row is still enabled
+	DisabledAt *int `json:"disabled_at"`
+}
+
+// TypedValue parses the value with its type
+func (r MetadataRecord) TypedValue() (MetadataValue, error) {
+	return ParseMetadataValue(r.Type, r.Value)
+}
+
+type GetMetadataParams struct {
+	Key MetadataKey
+	// OnlyLatest if true, will return the latest enabled row only
+	OnlyLatest bool
+	// Ref optional. Only returns rows with this ref value, i.e. a wallet address
+	Ref string
+}
+
+// MetadataInput is a row to be inserted
+type MetadataInput struct {
+	Key   MetadataKey
+	Value MetadataValue
+}
+
+// PermissionMetadataKeys are the keys that define who can read, write and compose a stream
+var PermissionMetadataKeys = []MetadataKey{
+	StreamOwner,
+	ReadVisibilityKey,
+	ComposeVisibilityKey,
+	AllowReadWalletKey,
+	AllowWriteWalletKey,
+	AllowComposeStreamKey,
+}
+
+type MetadataHistoryParams struct {
+	// Keys optional. If empty, rows of every key are returned
+	Keys []MetadataKey
+	// Ref optional. Only returns rows with this ref value, i.e. a wallet address
+	Ref string
+}
+
+// MetadataAuditLog is a list of metadata records, ordered by creation
+type MetadataAuditLog []MetadataRecord
+
+// WriteJSON writes the log as a JSON array
+func (l MetadataAuditLog) WriteJSON(w io.Writer) error {
+	records := l
+	if records == nil {
+		records = MetadataAuditLog{}
+	}
+	return errors.WithStack(json.NewEncoder(w).Encode(records))
+}
+
+// WriteCSV writes the log as CSV, with a header row. Empty disabled_at means the row is still enabled
+func (l MetadataAuditLog) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row_id", "key", "type", "value", "created_at", "disabled_at"}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, record := range l {
+		disabledAt := ""
+		if record.DisabledAt != nil {
+			disabledAt = strconv.Itoa(*record.DisabledAt)
+		}
+		err := writer.Write([]string{
+			record.RowId,
+			record.Key.String(),
+			string(record.Type),
+			record.Value,
+			strconv.Itoa(record.CreatedAt),
+			disabledAt,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	writer.Flush()
+	return errors.WithStack(writer.Error())
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"github.com/golang-sql/civil"
+	"github.com/pkg/errors"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// MetadataSnapshot is the metadata of a stream as it was at a block height
+type MetadataSnapshot struct {
+	Height int
+	// Records are the rows enabled at Height, ordered by creation
+	Records []MetadataRecord
+	// ComposeStreams are the locators of the allowed compose streams, by DBID. GetMetadataAt resolves
+	// them from the stored DBIDs, leaving out the streams that can't be resolved, i.e. dropped since
+	ComposeStreams map[string]StreamLocator
+}
+
+// AllowedComposeStream is a row of the compose allowlist
+type AllowedComposeStream struct {
+	// DBID is the raw value stored in the allowlist
+	DBID string
+	// Locator is nil if the DBID couldn't be resolved
+	Locator *StreamLocator
+}
+
+// NewMetadataSnapshot keeps the rows created at or before the height, and not yet disabled at it
+func NewMetadataSnapshot(history MetadataAuditLog, height int) MetadataSnapshot {
+	snapshot := MetadataSnapshot{Height: height}
+	for _, record := range history {
+		if record.CreatedAt > height {
+			continue
+		}
+		if record.DisabledAt != nil && *record.DisabledAt <= height {
+			continue
+		}
+		snapshot.Records = append(snapshot.Records, record)
+	}
+	return snapshot
+}
+
+// All returns the rows of a key
+func (s MetadataSnapshot) All(key MetadataKey) []MetadataRecord {
+	var records []MetadataRecord
+	for _, record := range s.Records {
+		if record.Key == key {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Latest returns the most recent row of a key, as get_metadata does with only_latest. Nil if there's none
+func (s MetadataSnapshot) Latest(key MetadataKey) *MetadataRecord {
+	var latest *MetadataRecord
+	for i, record := range s.Records {
+		if record.Key == key && (latest == nil || record.CreatedAt >= latest.CreatedAt) {
+			latest = &s.Records[i]
+		}
+	}
+	return latest
+}
+
+// ReadVisibility is nil if it wasn't set, which the contracts treat as public
+func (s MetadataSnapshot) ReadVisibility() (*util.VisibilityEnum, error) {
+	return s.visibility(ReadVisibilityKey)
+}
+
+// ComposeVisibility is nil if it wasn't set, which the contracts treat as public
+func (s MetadataSnapshot) ComposeVisibility() (*util.VisibilityEnum, error) {
+	return s.visibility(ComposeVisibilityKey)
+}
+
+func (s MetadataSnapshot) visibility(key MetadataKey) (*util.VisibilityEnum, error) {
+	record := s.Latest(key)
+	if record == nil {
+		return nil, nil
+	}
+
+	value, err := record.TypedValue()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	i, err := value.AsInt()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	visibility, err := util.NewVisibilityEnum(i)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &visibility, nil
+}
+
+func (s MetadataSnapshot) AllowedReadWallets() ([]util.EthereumAddress, error) {
+	return s.wallets(AllowReadWalletKey)
+}
+
+func (s MetadataSnapshot) AllowedWriteWallets() ([]util.EthereumAddress, error) {
+	return s.wallets(AllowWriteWalletKey)
+}
+
+func (s MetadataSnapshot) wallets(key MetadataKey) ([]util.EthereumAddress, error) {
+	records := s.All(key)
+	wallets := make([]util.EthereumAddress, len(records))
+	for i, record := range records {
+		address, err := util.NewEthereumAddressFromString(record.Value)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		wallets[i] = address
+	}
+	return wallets, nil
+}
+
+// AllowedComposeStreams are the streams allowed to compose, with the locators resolved by GetMetadataAt
+func (s MetadataSnapshot) AllowedComposeStreams() []AllowedComposeStream {
+	dbids := s.AllowedComposeStreamDBIDs()
+	streams := make([]AllowedComposeStream, len(dbids))
+	for i, dbid := range dbids {
+		streams[i] = AllowedComposeStream{DBID: dbid}
+		if locator, ok := s.ComposeStreams[dbid]; ok {
+			streams[i].Locator = &locator
+		}
+	}
+	return streams
+}
+
+// AllowedComposeStreamDBIDs are the raw values of the allowlist, which stores DBIDs and not locators
+func (s MetadataSnapshot) AllowedComposeStreamDBIDs() []string {
+	records := s.All(AllowComposeStreamKey)
+	dbids := make([]string, len(records))
+	for i, record := range records {
+		dbids[i] = record.Value
+	}
+	return dbids
+}
+
+// DefaultBaseDate is nil if it wasn't set
+func (s MetadataSnapshot) DefaultBaseDate() (*civil.Date, error) {
+	record := s.Latest(DefaultBaseDateKey)
+	if record == nil {
+		return nil, nil
+	}
+
+	date, err := civil.ParseDate(record.Value)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &date, nil
+}
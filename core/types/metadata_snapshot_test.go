@@ -0,0 +1,87 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// TestMetadataSnapshot checks reading the metadata as it was at past block heights.
+func TestMetadataSnapshot(t *testing.T) {
+	disabledAt := 20
+	readerA := "0x1111111111111111111111111111111111111111"
+	readerB := "0x2222222222222222222222222222222222222222"
+
+	history := types.MetadataAuditLog{
+		{RowId: "1", Key: types.StreamOwner, Type: types.MetadataTypeRef, Value: readerA, CreatedAt: 1},
+		{RowId: "2", Key: types.ReadVisibilityKey, Type: types.MetadataTypeInt, Value: "0", CreatedAt: 5},
+		{RowId: "3", Key: types.ReadVisibilityKey, Type: types.MetadataTypeInt, Value: "1", CreatedAt: 10},
+		{RowId: "4", Key: types.AllowReadWalletKey, Type: types.MetadataTypeRef, Value: readerA, CreatedAt: 10, DisabledAt: &disabledAt},
+		{RowId: "5", Key: types.AllowReadWalletKey, Type: types.MetadataTypeRef, Value: readerB, CreatedAt: 15},
+		{RowId: "6", Key: types.DefaultBaseDateKey, Type: types.MetadataTypeString, Value: "2020-01-01", CreatedAt: 12},
+	}
+
+	var walletAddresses = func(t *testing.T, snapshot types.MetadataSnapshot) []string {
+		wallets, err := snapshot.AllowedReadWallets()
+		require.NoError(t, err, "Failed to get allowed read wallets")
+		addresses := make([]string, len(wallets))
+		for i, wallet := range wallets {
+			addresses[i] = wallet.Address()
+		}
+		return addresses
+	}
+
+	t.Run("BeforeAnyChange", func(t *testing.T) {
+		snapshot := types.NewMetadataSnapshot(history, 4)
+		visibility, err := snapshot.ReadVisibility()
+		require.NoError(t, err, "Failed to get read visibility")
+		assert.Nil(t, visibility)
+
+		baseDate, err := snapshot.DefaultBaseDate()
+		require.NoError(t, err, "Failed to get default base date")
+		assert.Nil(t, baseDate)
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		snapshot := types.NewMetadataSnapshot(history, 15)
+		visibility, err := snapshot.ReadVisibility()
+		require.NoError(t, err, "Failed to get read visibility")
+		if assert.NotNil(t, visibility) {
+			assert.Equal(t, util.PrivateVisibility, *visibility)
+		}
+		assert.Equal(t, []string{readerA, readerB}, walletAddresses(t, snapshot))
+
+		baseDate, err := snapshot.DefaultBaseDate()
+		require.NoError(t, err, "Failed to get default base date")
+		if assert.NotNil(t, baseDate) {
+			assert.Equal(t, "2020-01-01", baseDate.String())
+		}
+	})
+
+	t.Run("AfterRevoke", func(t *testing.T) {
+		// the row is disabled at height 20, so it's no longer enabled at that height
+		snapshot := types.NewMetadataSnapshot(history, 20)
+		assert.Equal(t, []string{readerB}, walletAddresses(t, snapshot))
+	})
+
+	t.Run("ComposeStreams", func(t *testing.T) {
+		composeHistory := append(types.MetadataAuditLog{
+			{RowId: "7", Key: types.AllowComposeStreamKey, Type: types.MetadataTypeRef, Value: "x_dbid", CreatedAt: 12},
+		}, history...)
+		snapshot := types.NewMetadataSnapshot(composeHistory, 15)
+		assert.Equal(t, []string{"x_dbid"}, snapshot.AllowedComposeStreamDBIDs())
+
+		// the locators are resolved by GetMetadataAt, not from the rows
+		assert.Equal(t, []types.AllowedComposeStream{{DBID: "x_dbid"}}, snapshot.AllowedComposeStreams())
+
+		locator := types.StreamLocator{
+			StreamId:     util.GenerateStreamId("snapshot-compose"),
+			DataProvider: util.Unsafe_NewEthereumAddressFromString(readerA),
+		}
+		snapshot.ComposeStreams = map[string]types.StreamLocator{"x_dbid": locator}
+		assert.Equal(t, []types.AllowedComposeStream{{DBID: "x_dbid", Locator: &locator}}, snapshot.AllowedComposeStreams())
+	})
+}
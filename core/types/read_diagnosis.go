@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReadBlocker is a stream in a taxonomy that prevents a composed stream from being read
+type ReadBlocker struct {
+	Stream StreamLocator
+	// Parent is the composed stream using Stream as child, nil if Stream is the root
+	Parent *StreamLocator
+	// MissingPermission is ReadPermission if the wallet can't read Stream,
+	// or ComposePermission if Parent isn't allowed to compose Stream
+	MissingPermission StreamPermission
+}
+
+// ReadDiagnosis lists every stream that blocks a wallet from reading a composed stream
+type ReadDiagnosis struct {
+	Blockers []ReadBlocker
+}
+
+// IsReadable returns true if no stream blocks the read
+func (d ReadDiagnosis) IsReadable() bool {
+	return len(d.Blockers) == 0
+}
+
+// String renders one blocker per line, i.e.
+// stream st123 (0x...) missing read
+// stream st456 (0x...) missing compose for parent st789 (0x...)
+func (d ReadDiagnosis) String() string {
+	var sb strings.Builder
+	for _, blocker := range d.Blockers {
+		sb.WriteString(fmt.Sprintf("stream %s missing %s", locatorString(blocker.Stream), blocker.MissingPermission))
+		if blocker.Parent != nil {
+			sb.WriteString(fmt.Sprintf(" for parent %s", locatorString(*blocker.Parent)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
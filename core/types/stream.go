@@ -40,6 +40,8 @@ type IStream interface {
 	GetIndex(ctx context.Context, input GetIndexInput) ([]StreamIndex, error)
 	// GetType gets the type of the stream -- Primitive or Composed
 	GetType(ctx context.Context) (StreamType, error)
+	// Refresh drops the cached metadata of the stream, such as its type and owner, so it's fetched again when needed
+	Refresh()
 	// GetFirstRecord gets the first record of the stream
 	GetFirstRecord(ctx context.Context, input GetFirstRecordInput) (*StreamRecord, error)
 
@@ -56,6 +58,10 @@ type IStream interface {
 	AllowReadWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error)
 	// DisableReadWallet disables a wallet from reading the stream
 	DisableReadWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error)
+	// AllowWriteWallet allows a wallet to insert records into the stream
+	AllowWriteWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error)
+	// DisableWriteWallet disables a wallet from inserting records into the stream
+	DisableWriteWallet(ctx context.Context, wallet util.EthereumAddress) (transactions.TxHash, error)
 	// AllowComposeStream allows a stream to use this stream as child, if composing is private
 	AllowComposeStream(ctx context.Context, locator StreamLocator) (transactions.TxHash, error)
 	// DisableComposeStream disables a stream from using this stream as child
@@ -63,9 +69,51 @@ type IStream interface {
 
 	// GetAllowedReadWallets gets the wallets allowed to read the stream
 	GetAllowedReadWallets(ctx context.Context) ([]util.EthereumAddress, error)
+	// GetAllowedWriteWallets gets the wallets allowed to write to the stream, besides the owner
+	GetAllowedWriteWallets(ctx context.Context) ([]util.EthereumAddress, error)
 	// GetAllowedComposeStreams gets the streams allowed to compose this stream
 	GetAllowedComposeStreams(ctx context.Context) ([]StreamLocator, error)
 
-	// SetDefaultBaseDate insert a metadata row with `default_base_date` key
-	SetDefaultBaseDate(ctx context.Context, baseDate string) (transactions.TxHash, error)
+	// CanRead checks if the wallet is allowed to read the stream, considering visibility and allowlist
+	CanRead(ctx context.Context, wallet util.EthereumAddress) (bool, error)
+	// CanWrite checks if the wallet is allowed to write to the stream. For composed streams, only the owner can write
+	CanWrite(ctx context.Context, wallet util.EthereumAddress) (bool, error)
+	// CanCompose checks if the given stream is allowed to use this stream as child
+	CanCompose(ctx context.Context, locator StreamLocator) (bool, error)
+
+	// GetMetadata gets the enabled metadata rows of a key, most recent first
+	GetMetadata(ctx context.Context, params GetMetadataParams) ([]MetadataRecord, error)
+	// InsertMetadata inserts a metadata row. Well-known keys must be given a value of their type
+	InsertMetadata(ctx context.Context, key MetadataKey, value MetadataValue) (transactions.TxHash, error)
+	// BatchInsertMetadata inserts many metadata rows in a single transaction
+	BatchInsertMetadata(ctx context.Context, inputs []MetadataInput) (transactions.TxHash, error)
+	// DisableMetadata disables a metadata row by its row id
+	DisableMetadata(ctx context.Context, rowId string) (transactions.TxHash, error)
+	// BatchDisableMetadata disables many metadata rows in a single transaction
+	BatchDisableMetadata(ctx context.Context, rowIds []string) (transactions.TxHash, error)
+	// GetMetadataHistory gets metadata rows, including disabled ones, with the block heights they were created and disabled at
+	GetMetadataHistory(ctx context.Context, params MetadataHistoryParams) (MetadataAuditLog, error)
+	// GetMetadataAt gets the metadata as it was at a block height, i.e. to know if the stream was public back then
+	GetMetadataAt(ctx context.Context, height int) (MetadataSnapshot, error)
+
+	// PlanACL computes the changes needed to get from the current permissions to the desired ones
+	PlanACL(ctx context.Context, desired StreamACL) (ACLPlan, error)
+	// ApplyACLPlan applies a plan computed by PlanACL, using at most one transaction for inserts and one for disables
+	ApplyACLPlan(ctx context.Context, plan ACLPlan) ([]transactions.TxHash, error)
+
+	// GetDisplayName gets the human-readable name of the stream, empty if not set
+	GetDisplayName(ctx context.Context) (string, error)
+	// SetDescription sets the non-empty fields of the description, in a single transaction
+	SetDescription(ctx context.Context, description StreamDescription) (transactions.TxHash, error)
+	// ClearDescriptionFields unsets the description fields stored under the given keys, in a single transaction
+	ClearDescriptionFields(ctx context.Context, keys ...MetadataKey) (transactions.TxHash, error)
+	// GetDescription gets the description of the stream. Fields not set are empty
+	GetDescription(ctx context.Context) (StreamDescription, error)
+
+	// SetDefaultBaseDate sets the date get_index uses as base when none is given
+	SetDefaultBaseDate(ctx context.Context, baseDate civil.Date) (transactions.TxHash, error)
+	// GetDefaultBaseDate gets the default base date, nil if not set
+	GetDefaultBaseDate(ctx context.Context) (*civil.Date, error)
+	// ClearDefaultBaseDate disables the default base date, so get_index falls back to the first record
+	ClearDefaultBaseDate(ctx context.Context) (transactions.TxHash, error)
 }
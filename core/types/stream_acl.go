@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// StreamACL is the desired permission state of a stream.
+// Nil fields are left untouched. An empty, non-nil allowlist revokes every grant of that kind
+type StreamACL struct {
+	ReadVisibility        *util.VisibilityEnum
+	ComposeVisibility     *util.VisibilityEnum
+	AllowedReadWallets    []util.EthereumAddress
+	AllowedWriteWallets   []util.EthereumAddress
+	AllowedComposeStreams []StreamLocator
+}
+
+// ACLChange is a single metadata row to be inserted or disabled
+type ACLChange struct {
+	Key MetadataKey
+	// Value is the visibility, the wallet address or the DBID of the composing stream
+	Value string
+	// RowId is the row to be disabled. Empty for inserts
+	RowId string
+}
+
+// ACLPlan are the metadata changes needed to reach a StreamACL
+type ACLPlan struct {
+	// Inserts are sent together in a single transaction
+	Inserts []ACLChange
+	// Disables are sent together in a single transaction
+	Disables []ACLChange
+}
+
+// IsEmpty returns true if the current permissions already match the desired ones
+func (p ACLPlan) IsEmpty() bool {
+	return len(p.Inserts) == 0 && len(p.Disables) == 0
+}
+
+// String renders the plan one change per line, i.e.
+// + read_visibility 1
+// + allow_read_wallet 0x...
+// - allow_read_wallet 0x... (row 3fa85f64-5717-4562-b3fc-2c963f66afa6)
+func (p ACLPlan) String() string {
+	var sb strings.Builder
+	for _, change := range p.Inserts {
+		sb.WriteString(fmt.Sprintf("+ %s %s\n", change.Key, change.Value))
+	}
+	for _, change := range p.Disables {
+		sb.WriteString(fmt.Sprintf("- %s %s (row %s)\n", change.Key, change.Value, change.RowId))
+	}
+	return sb.String()
+}
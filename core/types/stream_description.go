@@ -0,0 +1,86 @@
+package types
+
+type StreamFrequency string
+
+const (
+	FrequencyDaily     StreamFrequency = "daily"
+	FrequencyWeekly    StreamFrequency = "weekly"
+	FrequencyMonthly   StreamFrequency = "monthly"
+	FrequencyQuarterly StreamFrequency = "quarterly"
+	FrequencyYearly    StreamFrequency = "yearly"
+)
+
+// StreamDescription tells what a stream measures, so catalogs can show more than its id.
+// Every field is stored under its own metadata key
+type StreamDescription struct {
+	// DisplayName is stored under `display_name`
+	DisplayName string
+	// Description is stored under `description`
+	Description string
+	// Unit of the values, i.e. "USD" or "%". Stored under `unit`
+	Unit string
+	// Frequency at which records are published. Stored under `frequency`
+	Frequency StreamFrequency
+	// SourceURL is where the data comes from. Stored under `source_url`
+	SourceURL string
+	// MethodologyVersion is stored under `methodology_version`
+	MethodologyVersion string
+}
+
+// DescriptionMetadataKeys are the keys a StreamDescription is stored under
+var DescriptionMetadataKeys = []MetadataKey{
+	DisplayNameKey,
+	DescriptionKey,
+	UnitKey,
+	FrequencyKey,
+	SourceURLKey,
+	MethodologyVersionKey,
+}
+
+// Fields maps every key to its value in the description
+func (d StreamDescription) Fields() map[MetadataKey]string {
+	return map[MetadataKey]string{
+		DisplayNameKey:        d.DisplayName,
+		DescriptionKey:        d.Description,
+		UnitKey:               d.Unit,
+		FrequencyKey:          string(d.Frequency),
+		SourceURLKey:          d.SourceURL,
+		MethodologyVersionKey: d.MethodologyVersion,
+	}
+}
+
+// setField sets the field stored under the given key. Other keys are ignored
+func (d *StreamDescription) setField(key MetadataKey, value string) {
+	switch key {
+	case DisplayNameKey:
+		d.DisplayName = value
+	case DescriptionKey:
+		d.Description = value
+	case UnitKey:
+		d.Unit = value
+	case FrequencyKey:
+		d.Frequency = StreamFrequency(value)
+	case SourceURLKey:
+		d.SourceURL = value
+	case MethodologyVersionKey:
+		d.MethodologyVersion = value
+	}
+}
+
+// StreamDescriptionFromMetadata builds a description from metadata rows. Disabled rows are skipped,
+// and the latest enabled row of each key wins
+func StreamDescriptionFromMetadata(records []MetadataRecord) StreamDescription {
+	var description StreamDescription
+	latest := make(map[MetadataKey]int)
+	for _, record := range records {
+		if record.DisabledAt != nil {
+			continue
+		}
+		if createdAt, ok := latest[record.Key]; ok && createdAt > record.CreatedAt {
+			continue
+		}
+		latest[record.Key] = record.CreatedAt
+		description.setField(record.Key, record.Value)
+	}
+	return description
+}
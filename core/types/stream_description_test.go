@@ -0,0 +1,26 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// TestStreamDescriptionFromMetadata checks that the latest enabled row of each key wins.
+func TestStreamDescriptionFromMetadata(t *testing.T) {
+	disabledAt := 9
+	description := types.StreamDescriptionFromMetadata([]types.MetadataRecord{
+		{Key: types.DisplayNameKey, Type: types.MetadataTypeString, Value: "Old name", CreatedAt: 1},
+		{Key: types.DisplayNameKey, Type: types.MetadataTypeString, Value: "New name", CreatedAt: 5},
+		{Key: types.UnitKey, Type: types.MetadataTypeString, Value: "USD", CreatedAt: 2},
+		{Key: types.UnitKey, Type: types.MetadataTypeString, Value: "EUR", CreatedAt: 3, DisabledAt: &disabledAt},
+		{Key: types.FrequencyKey, Type: types.MetadataTypeString, Value: "daily", CreatedAt: 2},
+	})
+
+	assert.Equal(t, types.StreamDescription{
+		DisplayName: "New name",
+		Unit:        "USD",
+		Frequency:   types.FrequencyDaily,
+	}, description)
+}
@@ -9,3 +9,9 @@ type StreamLocator struct {
 	// DataProvider is the address of the data provider, it's the deployer of the stream
 	DataProvider util.EthereumAddress
 }
+
+// Equals reports whether both locators point to the same stream
+func (s StreamLocator) Equals(other StreamLocator) bool {
+	return s.StreamId.String() == other.StreamId.String() &&
+		s.DataProvider.Address() == other.DataProvider.Address()
+}
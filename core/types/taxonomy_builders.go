@@ -0,0 +1,119 @@
+package types
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang-sql/civil"
+	"github.com/pkg/errors"
+)
+
+// TaxonomyShare is the share of a child, in any unit, i.e. percentage of expenditure
+type TaxonomyShare struct {
+	ChildStream StreamLocator
+	Share       float64
+}
+
+// TaxonomyBasketItem is a child priced by quantity, i.e. items of a consumer basket
+type TaxonomyBasketItem struct {
+	ChildStream StreamLocator
+	Quantity    float64
+	Price       float64
+}
+
+type TaxonomyBuilderOptions struct {
+	// StartDate optional. Date from which the taxonomy is valid
+	StartDate *civil.Date
+	// Tolerance is the accepted absolute difference between the sum of the shares and the expected total.
+	// If zero, a relative tolerance of 1e-6 of the expected total is used. It can't be negative
+	Tolerance float64
+}
+
+// NewEqualWeightTaxonomy builds a taxonomy where every child has the same weight
+func NewEqualWeightTaxonomy(children []StreamLocator, opts TaxonomyBuilderOptions) (Taxonomy, error) {
+	shares := make([]TaxonomyShare, len(children))
+	for i, child := range children {
+		shares[i] = TaxonomyShare{ChildStream: child, Share: 1}
+	}
+	return NewTaxonomyFromShares(shares, float64(len(children)), opts)
+}
+
+// NewTaxonomyFromPercentages builds a taxonomy from shares that must sum to 100
+func NewTaxonomyFromPercentages(percentages []TaxonomyShare, opts TaxonomyBuilderOptions) (Taxonomy, error) {
+	return NewTaxonomyFromShares(percentages, 100, opts)
+}
+
+// NewTaxonomyFromBasket builds a taxonomy where each child weighs quantity * price
+func NewTaxonomyFromBasket(items []TaxonomyBasketItem, opts TaxonomyBuilderOptions) (Taxonomy, error) {
+	shares := make([]TaxonomyShare, len(items))
+	total := 0.0
+	for i, item := range items {
+		if err := checkFinitePositive(item.Quantity, "quantity", item.ChildStream); err != nil {
+			return Taxonomy{}, err
+		}
+		if err := checkFinitePositive(item.Price, "price", item.ChildStream); err != nil {
+			return Taxonomy{}, err
+		}
+		shares[i] = TaxonomyShare{ChildStream: item.ChildStream, Share: item.Quantity * item.Price}
+		total += shares[i].Share
+	}
+	return NewTaxonomyFromShares(shares, total, opts)
+}
+
+// NewTaxonomyFromShares builds a taxonomy from shares that must sum to expectedTotal.
+// Weights are normalised, so they sum to 1
+func NewTaxonomyFromShares(shares []TaxonomyShare, expectedTotal float64, opts TaxonomyBuilderOptions) (Taxonomy, error) {
+	if len(shares) == 0 {
+		return Taxonomy{}, errors.New("taxonomy must have at least one child")
+	}
+	if err := checkFinitePositive(expectedTotal, "expected total", StreamLocator{}); err != nil {
+		return Taxonomy{}, err
+	}
+	if math.IsNaN(opts.Tolerance) || opts.Tolerance < 0 {
+		return Taxonomy{}, errors.New(fmt.Sprintf("tolerance must be zero or positive, got %v", opts.Tolerance))
+	}
+
+	total := 0.0
+	for i, share := range shares {
+		if err := checkFinitePositive(share.Share, "share", share.ChildStream); err != nil {
+			return Taxonomy{}, err
+		}
+		for _, previous := range shares[:i] {
+			if previous.ChildStream.Equals(share.ChildStream) {
+				return Taxonomy{}, errors.New(fmt.Sprintf("duplicate child stream %s", share.ChildStream.StreamId.String()))
+			}
+		}
+		total += share.Share
+	}
+
+	tolerance := opts.Tolerance
+	if tolerance == 0 {
+		tolerance = expectedTotal * 1e-6
+	}
+	if math.Abs(total-expectedTotal) > tolerance {
+		return Taxonomy{}, errors.New(fmt.Sprintf("shares sum to %v, expected %v", total, expectedTotal))
+	}
+
+	items := make([]TaxonomyItem, len(shares))
+	for i, share := range shares {
+		items[i] = TaxonomyItem{
+			ChildStream: share.ChildStream,
+			Weight:      share.Share / total,
+		}
+	}
+
+	return Taxonomy{
+		TaxonomyItems: items,
+		StartDate:     opts.StartDate,
+	}, nil
+}
+
+func checkFinitePositive(value float64, name string, child StreamLocator) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		if child.StreamId.String() == "" {
+			return errors.New(fmt.Sprintf("%s must be a positive number, got %v", name, value))
+		}
+		return errors.New(fmt.Sprintf("%s of child stream %s must be a positive number, got %v", name, child.StreamId.String(), value))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+)
+
+// TestTaxonomyBuilders checks weights built from percentages, baskets and equal weighting.
+func TestTaxonomyBuilders(t *testing.T) {
+	dataProvider := util.Unsafe_NewEthereumAddressFromString("0x0000000000000000000000000000000000000001")
+	childA := types.StreamLocator{StreamId: util.GenerateStreamId("taxonomy-builders-a"), DataProvider: dataProvider}
+	childB := types.StreamLocator{StreamId: util.GenerateStreamId("taxonomy-builders-b"), DataProvider: dataProvider}
+	childC := types.StreamLocator{StreamId: util.GenerateStreamId("taxonomy-builders-c"), DataProvider: dataProvider}
+
+	var checkWeights = func(t *testing.T, taxonomy types.Taxonomy, expected ...float64) {
+		if !assert.Equal(t, len(expected), len(taxonomy.TaxonomyItems)) {
+			return
+		}
+		for i, item := range taxonomy.TaxonomyItems {
+			assert.InDelta(t, expected[i], item.Weight, 1e-12)
+		}
+	}
+
+	t.Run("EqualWeights", func(t *testing.T) {
+		taxonomy, err := types.NewEqualWeightTaxonomy([]types.StreamLocator{childA, childB, childC}, types.TaxonomyBuilderOptions{
+			StartDate: unsafeParseDate("2024-01-01"),
+		})
+		require.NoError(t, err, "Failed to build taxonomy")
+		checkWeights(t, taxonomy, 1.0/3, 1.0/3, 1.0/3)
+		assert.Equal(t, "2024-01-01", taxonomy.StartDate.String())
+	})
+
+	t.Run("Percentages", func(t *testing.T) {
+		taxonomy, err := types.NewTaxonomyFromPercentages([]types.TaxonomyShare{
+			{ChildStream: childA, Share: 62.5},
+			{ChildStream: childB, Share: 37.5},
+		}, types.TaxonomyBuilderOptions{})
+		require.NoError(t, err, "Failed to build taxonomy")
+		checkWeights(t, taxonomy, 0.625, 0.375)
+
+		// published shares are usually rounded
+		taxonomy, err = types.NewTaxonomyFromPercentages([]types.TaxonomyShare{
+			{ChildStream: childA, Share: 33.334},
+			{ChildStream: childB, Share: 33.333},
+			{ChildStream: childC, Share: 33.334},
+		}, types.TaxonomyBuilderOptions{Tolerance: 0.01})
+		require.NoError(t, err, "Failed to build taxonomy")
+		sum := 0.0
+		for _, item := range taxonomy.TaxonomyItems {
+			sum += item.Weight
+		}
+		assert.InDelta(t, 1, sum, 1e-12)
+
+		_, err = types.NewTaxonomyFromPercentages([]types.TaxonomyShare{
+			{ChildStream: childA, Share: 60},
+			{ChildStream: childB, Share: 30},
+		}, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "shares not summing to 100 should be rejected")
+
+		// a negative tolerance would reject every taxonomy, even an exact one
+		_, err = types.NewTaxonomyFromPercentages([]types.TaxonomyShare{
+			{ChildStream: childA, Share: 62.5},
+			{ChildStream: childB, Share: 37.5},
+		}, types.TaxonomyBuilderOptions{Tolerance: -0.01})
+		if assert.Error(t, err, "negative tolerances should be rejected") {
+			assert.Contains(t, err.Error(), "tolerance")
+		}
+	})
+
+	t.Run("Basket", func(t *testing.T) {
+		taxonomy, err := types.NewTaxonomyFromBasket([]types.TaxonomyBasketItem{
+			{ChildStream: childA, Quantity: 2, Price: 5},
+			{ChildStream: childB, Quantity: 10, Price: 3},
+		}, types.TaxonomyBuilderOptions{})
+		require.NoError(t, err, "Failed to build taxonomy")
+		checkWeights(t, taxonomy, 0.25, 0.75)
+	})
+
+	t.Run("DegenerateInput", func(t *testing.T) {
+		_, err := types.NewEqualWeightTaxonomy(nil, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "empty taxonomy should be rejected")
+
+		_, err = types.NewTaxonomyFromShares([]types.TaxonomyShare{
+			{ChildStream: childA, Share: 1},
+			{ChildStream: childA, Share: 1},
+		}, 2, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "duplicate children should be rejected")
+
+		_, err = types.NewTaxonomyFromShares([]types.TaxonomyShare{
+			{ChildStream: childA, Share: -1},
+			{ChildStream: childB, Share: 2},
+		}, 1, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "negative shares should be rejected")
+
+		_, err = types.NewTaxonomyFromBasket([]types.TaxonomyBasketItem{
+			{ChildStream: childA, Quantity: math.NaN(), Price: 1},
+		}, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "NaN quantities should be rejected")
+
+		_, err = types.NewTaxonomyFromBasket([]types.TaxonomyBasketItem{
+			{ChildStream: childA, Quantity: 1, Price: 0},
+		}, types.TaxonomyBuilderOptions{})
+		assert.Error(t, err, "zero prices should be rejected")
+	})
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaxonomyWeightChange describes a child that is present in both versions with a different weight
+type TaxonomyWeightChange struct {
+	ChildStream    StreamLocator
+	PreviousWeight float64
+	NextWeight     float64
+}
+
+// TaxonomyDiff is the difference between two taxonomy versions
+type TaxonomyDiff struct {
+	Added      []TaxonomyItem
+	Removed    []TaxonomyItem
+	Reweighted []TaxonomyWeightChange
+}
+
+// DiffTaxonomies compares the children of two taxonomies. Start dates are not compared
+func DiffTaxonomies(previous, next Taxonomy) TaxonomyDiff {
+	var diff TaxonomyDiff
+
+	for _, nextItem := range next.TaxonomyItems {
+		previousItem, found := findTaxonomyItem(previous.TaxonomyItems, nextItem.ChildStream)
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, nextItem)
+		case previousItem.Weight != nextItem.Weight:
+			diff.Reweighted = append(diff.Reweighted, TaxonomyWeightChange{
+				ChildStream:    nextItem.ChildStream,
+				PreviousWeight: previousItem.Weight,
+				NextWeight:     nextItem.Weight,
+			})
+		}
+	}
+
+	for _, previousItem := range previous.TaxonomyItems {
+		if _, found := findTaxonomyItem(next.TaxonomyItems, previousItem.ChildStream); !found {
+			diff.Removed = append(diff.Removed, previousItem)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns true if both versions have the same children with the same weights
+func (d TaxonomyDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Reweighted) == 0
+}
+
+// String renders the diff one child per line, i.e.
+// + st906974fb3f30a28200e907c604b15b (0x...) 1.000000
+// - st906974fb3f30a28200e907c604b15b (0x...) 2.000000
+// ~ st906974fb3f30a28200e907c604b15b (0x...) 1.000000 -> 3.000000
+func (d TaxonomyDiff) String() string {
+	var sb strings.Builder
+	for _, item := range d.Added {
+		sb.WriteString(fmt.Sprintf("+ %s %f\n", locatorString(item.ChildStream), item.Weight))
+	}
+	for _, item := range d.Removed {
+		sb.WriteString(fmt.Sprintf("- %s %f\n", locatorString(item.ChildStream), item.Weight))
+	}
+	for _, change := range d.Reweighted {
+		sb.WriteString(fmt.Sprintf("~ %s %f -> %f\n", locatorString(change.ChildStream), change.PreviousWeight, change.NextWeight))
+	}
+	return sb.String()
+}
+
+func findTaxonomyItem(items []TaxonomyItem, locator StreamLocator) (TaxonomyItem, bool) {
+	for _, item := range items {
+		if item.ChildStream.Equals(locator) {
+			return item, true
+		}
+	}
+	return TaxonomyItem{}, false
+}
+
+func locatorString(locator StreamLocator) string {
+	return fmt.Sprintf("%s (%s)", locator.StreamId.String(), locator.DataProvider.Address())
+}
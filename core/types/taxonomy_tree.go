@@ -0,0 +1,28 @@
+package types
+
+import "github.com/trufnetwork/sdk-go/core/util"
+
+// TaxonomyNode is a stream in a taxonomy tree. Primitive streams are leaves
+type TaxonomyNode struct {
+	Stream            StreamLocator
+	Type              StreamType
+	ReadVisibility    *util.VisibilityEnum
+	ComposeVisibility *util.VisibilityEnum
+	// DisplayName is the human-readable name from the stream metadata, if requested and set
+	DisplayName string
+	// Children are the children of the latest taxonomy version, for composed streams
+	Children []TaxonomyEdge
+}
+
+// TaxonomyEdge links a composed stream to one of its children
+type TaxonomyEdge struct {
+	Weight float64
+	Child  *TaxonomyNode
+}
+
+type DescribeTaxonomyTreeParams struct {
+	// IncludeDisplayNames if true, will also fetch the display name of every stream
+	IncludeDisplayNames bool
+	// MaxDepth limits how deep the tree is walked. 0 means down to the leaves
+	MaxDepth int
+}
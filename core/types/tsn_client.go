@@ -11,6 +11,8 @@ import (
 type Client interface {
 	// WaitForTx waits for the transaction to be mined by TN
 	WaitForTx(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error)
+	// WaitForTxSuccess waits for the transaction to be mined, failing with a *TxFailedError if its result is not OK
+	WaitForTxSuccess(ctx context.Context, txHash transactions.TxHash, interval time.Duration) (*transactions.TcTxQueryResponse, error)
 	// GetKwilClient returns the kwil client used by the client
 	GetKwilClient() *kwilClientPkg.Client
 	// DeployStream deploys a new stream
@@ -23,6 +25,12 @@ type Client interface {
 	LoadPrimitiveStream(stream StreamLocator) (IPrimitiveStream, error)
 	// LoadComposedStream loads a already deployed composed stream, permitting its API usage
 	LoadComposedStream(stream StreamLocator) (IComposedStream, error)
+	// LoadStreamContext is LoadStream, using the given context for its request
+	LoadStreamContext(ctx context.Context, stream StreamLocator) (IStream, error)
+	// LoadPrimitiveStreamContext is LoadPrimitiveStream, using the given context for its request
+	LoadPrimitiveStreamContext(ctx context.Context, stream StreamLocator) (IPrimitiveStream, error)
+	// LoadComposedStreamContext is LoadComposedStream, using the given context for its request
+	LoadComposedStreamContext(ctx context.Context, stream StreamLocator) (IComposedStream, error)
 	/*
 	 * utils for the client
 	 */
@@ -35,7 +43,15 @@ type Client interface {
 	// GetAllInitializedStreams returns all streams from the Truf Network that are initialized
 	GetAllInitializedStreams(ctx context.Context, input GetAllStreamsInput) ([]StreamLocator, error)
 	// DeployComposedStreamWithTaxonomy deploys a composed stream with a taxonomy
-	DeployComposedStreamWithTaxonomy(ctx context.Context, streamId util.StreamId, taxonomy Taxonomy) error
+	DeployComposedStreamWithTaxonomy(ctx context.Context, streamId util.StreamId, taxonomy Taxonomy, opts ...SetTaxonomyOption) error
+	// DescribeTaxonomyTree walks the taxonomy of a stream down to its leaves
+	DescribeTaxonomyTree(ctx context.Context, root StreamLocator, params DescribeTaxonomyTreeParams) (*TaxonomyNode, error)
+	// DiagnoseComposedRead finds the streams that block a wallet from reading a stream
+	DiagnoseComposedRead(ctx context.Context, locator StreamLocator, wallet util.EthereumAddress) (ReadDiagnosis, error)
+	// BulkGrantPermission grants a permission on many streams, reporting the outcome per stream
+	BulkGrantPermission(ctx context.Context, params BulkPermissionParams) (BulkPermissionResults, error)
+	// BulkRevokePermission revokes a permission on many streams, reporting the outcome per stream
+	BulkRevokePermission(ctx context.Context, params BulkPermissionParams) (BulkPermissionResults, error)
 }
 
 type GetAllStreamsInput struct {
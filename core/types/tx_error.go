@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+)
+
+// TxFailedError is returned when a transaction is mined with a non-OK result
+type TxFailedError struct {
+	TxHash transactions.TxHash
+	Code   transactions.TxCode
+	// Log is the raw log of the result
+	Log string
+	// Message is the error raised by the contract, parsed from the log
+	Message string
+}
+
+func NewTxFailedError(txHash transactions.TxHash, result transactions.TransactionResult) *TxFailedError {
+	return &TxFailedError{
+		TxHash:  txHash,
+		Code:    transactions.TxCode(result.Code),
+		Log:     result.Log,
+		Message: ParseTxLog(result.Log),
+	}
+}
+
+func (e *TxFailedError) Error() string {
+	return fmt.Sprintf("transaction %s failed with code %d (%s): %s", e.TxHash.Hex(), e.Code.Uint32(), e.Code, e.Message)
+}
+
+// Unwrap returns the sentinel of the contract error, so it can be matched with errors.Is
+func (e *TxFailedError) Unwrap() error {
+	return ContractErrorFromMessage(e.Message)
+}
+
+var sqlStateSuffix = regexp.MustCompile(`\s*\(SQLSTATE \w+\)\s*$`)
+
+// ParseTxLog extracts the error message from a transaction log, i.e.
+// "ERROR: wallet not allowed to write (SQLSTATE P0001)" -> "wallet not allowed to write".
+// Logs in other formats are returned trimmed
+func ParseTxLog(log string) string {
+	message := log
+	if i := strings.LastIndex(message, "ERROR: "); i >= 0 {
+		message = message[i+len("ERROR: "):]
+	}
+	message = sqlStateSuffix.ReplaceAllString(message, "")
+	return strings.TrimSpace(message)
+}
@@ -0,0 +1,29 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/types"
+)
+
+// TestTxFailedError checks the error returned for transactions mined with a non-OK result.
+func TestTxFailedError(t *testing.T) {
+	assert.Equal(t, "wallet not allowed to write", types.ParseTxLog("ERROR: wallet not allowed to write (SQLSTATE P0001)"))
+	assert.Equal(t, "Stream owner only procedure", types.ParseTxLog("failed to execute: ERROR: Stream owner only procedure (SQLSTATE P0001)"))
+	assert.Equal(t, "insufficient balance", types.ParseTxLog(" insufficient balance "))
+
+	var err error = types.NewTxFailedError(transactions.TxHash{0xab}, transactions.TransactionResult{
+		Code: uint32(transactions.CodeUnknownError),
+		Log:  "ERROR: base value is 0 (SQLSTATE P0001)",
+	})
+
+	var txErr *types.TxFailedError
+	if assert.True(t, errors.As(errors.WithStack(err), &txErr)) {
+		assert.Equal(t, transactions.CodeUnknownError, txErr.Code)
+		assert.Equal(t, "base value is 0", txErr.Message)
+	}
+	assert.Contains(t, err.Error(), "base value is 0")
+}
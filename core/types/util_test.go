@@ -0,0 +1,12 @@
+package types_test
+
+import "github.com/golang-sql/civil"
+
+// unsafeParseDate parses a date string into a civil.Date, panicking on error
+func unsafeParseDate(dateStr string) *civil.Date {
+	date, err := civil.ParseDate(dateStr)
+	if err != nil {
+		panic(err)
+	}
+	return &date
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/trufnetwork/sdk-go/core/logging"
-	"go.uber.org/zap"
 	"regexp"
 	"strings"
 )
@@ -43,7 +41,7 @@ func NewEthereumAddressFromBytes(address []byte) (EthereumAddress, error) {
 func Unsafe_NewEthereumAddressFromString(address string) EthereumAddress {
 	e, err := NewEthereumAddressFromString(address)
 	if err != nil {
-		logging.Logger.Panic("error creating ethereum address", zap.Error(err))
+		panic(fmt.Sprintf("error creating ethereum address: %v", err))
 	}
 	return e
 }
@@ -63,7 +61,7 @@ func (e *EthereumAddress) validate() error {
 
 func (e *EthereumAddress) checkCorrectlyCreated() {
 	if !e.correctlyCreated {
-		logging.Logger.Panic("please create an EthereumAddress with NewEthereumAddress")
+		panic("please create an EthereumAddress with NewEthereumAddress")
 	}
 }
 
@@ -79,7 +77,7 @@ func (e *EthereumAddress) Bytes() []byte {
 	// decode the hex string to bytes (remove the 0x prefix first)
 	bytes, err := hex.DecodeString(e.hex[2:])
 	if err != nil {
-		logging.Logger.Panic("error decoding hex string to bytes", zap.Error(err))
+		panic(fmt.Sprintf("error decoding hex string to bytes: %v", err))
 	}
 	return bytes
 }
@@ -24,6 +24,17 @@ func NewVisibilityEnum(value int) (VisibilityEnum, error) {
 	}
 }
 
+func (v VisibilityEnum) String() string {
+	switch v {
+	case PublicVisibility:
+		return "public"
+	case PrivateVisibility:
+		return "private"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(v))
+	}
+}
+
 // UnmarshalJSON unmarshals the visibility enum, also checking if the value is valid
 func (v *VisibilityEnum) UnmarshalJSON(data []byte) error {
 	var value int
@@ -0,0 +1,147 @@
+// Package kwiltest has a fake kwil client, for the tests that don't need a running node
+package kwiltest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kwiltypes "github.com/kwilteam/kwil-db/core/types"
+	kwilClientType "github.com/kwilteam/kwil-db/core/types/client"
+	"github.com/kwilteam/kwil-db/core/types/transactions"
+)
+
+// Client is a fake node. Each request it answers is counted, and answered by its hook if set, or else
+// by a deployed dataset without any record. Requests it doesn't answer panic
+type Client struct {
+	kwilClientType.Client
+
+	GetSchemaFunc func(ctx context.Context, dbid string) (*kwiltypes.Schema, error)
+	CallFunc      func(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error)
+	QueryFunc     func(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error)
+	// ExecuteFunc gets the options of the transaction already applied
+	ExecuteFunc    func(ctx context.Context, dbid string, action string, tuples [][]any, txOpts *kwilClientType.TxOptions) (transactions.TxHash, error)
+	GetAccountFunc func(ctx context.Context, pubKey []byte) (*kwiltypes.Account, error)
+	WaitTxFunc     func(ctx context.Context, txHash []byte) (*transactions.TcTxQueryResponse, error)
+
+	mu       sync.Mutex
+	err      error
+	nextErrs []error
+	requests map[string]int
+}
+
+var _ kwilClientType.Client = (*Client)(nil)
+
+// FailWith fails every ping, view call and broadcast with err, until it's called again with nil
+func (c *Client) FailWith(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// FailNext fails the next pings, view calls and broadcasts with errs, in order
+func (c *Client) FailNext(errs ...error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextErrs = append(c.nextErrs, errs...)
+}
+
+// Requests returns how many times the method was requested, i.e. "Call"
+func (c *Client) Requests(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests[method]
+}
+
+func (c *Client) request(method string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.requests == nil {
+		c.requests = make(map[string]int)
+	}
+	c.requests[method]++
+}
+
+// requestOrFail counts the request, and returns the error it must fail with, if any
+func (c *Client) requestOrFail(method string) error {
+	c.request(method)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.nextErrs) > 0 {
+		err := c.nextErrs[0]
+		c.nextErrs = c.nextErrs[1:]
+		return err
+	}
+	return c.err
+}
+
+func (c *Client) Ping(ctx context.Context) (string, error) {
+	if err := c.requestOrFail("Ping"); err != nil {
+		return "", err
+	}
+	return "pong", nil
+}
+
+func (c *Client) GetSchema(ctx context.Context, dbid string) (*kwiltypes.Schema, error) {
+	c.request("GetSchema")
+	if c.GetSchemaFunc != nil {
+		return c.GetSchemaFunc(ctx, dbid)
+	}
+	return &kwiltypes.Schema{}, nil
+}
+
+func (c *Client) Call(ctx context.Context, dbid string, procedure string, inputs []any) (*kwilClientType.Records, error) {
+	if err := c.requestOrFail("Call"); err != nil {
+		return nil, err
+	}
+	if c.CallFunc != nil {
+		return c.CallFunc(ctx, dbid, procedure, inputs)
+	}
+	return kwilClientType.NewRecordsFromMaps(nil), nil
+}
+
+func (c *Client) Query(ctx context.Context, dbid string, query string) (*kwilClientType.Records, error) {
+	c.request("Query")
+	if c.QueryFunc != nil {
+		return c.QueryFunc(ctx, dbid, query)
+	}
+	return kwilClientType.NewRecordsFromMaps(nil), nil
+}
+
+func (c *Client) Execute(ctx context.Context, dbid string, action string, tuples [][]any, opts ...kwilClientType.TxOpt) (transactions.TxHash, error) {
+	txOpts := &kwilClientType.TxOptions{}
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+
+	if err := c.requestOrFail("Execute"); err != nil {
+		return nil, err
+	}
+	if c.ExecuteFunc != nil {
+		return c.ExecuteFunc(ctx, dbid, action, tuples, txOpts)
+	}
+	return transactions.TxHash{1}, nil
+}
+
+func (c *Client) GetAccount(ctx context.Context, pubKey []byte, status kwiltypes.AccountStatus) (*kwiltypes.Account, error) {
+	c.request("GetAccount")
+	if c.GetAccountFunc != nil {
+		return c.GetAccountFunc(ctx, pubKey)
+	}
+	return &kwiltypes.Account{}, nil
+}
+
+func (c *Client) WaitTx(ctx context.Context, txHash []byte, interval time.Duration) (*transactions.TcTxQueryResponse, error) {
+	c.request("WaitTx")
+	if c.WaitTxFunc != nil {
+		return c.WaitTxFunc(ctx, txHash)
+	}
+	return &transactions.TcTxQueryResponse{TxResult: transactions.TransactionResult{Code: transactions.CodeOk.Uint32()}}, nil
+}
+
+// NewSignedTx builds the transaction unsigned, so the fake can be the builder of dry runs
+func (c *Client) NewSignedTx(ctx context.Context, data transactions.Payload, txOpts *kwilClientType.TxOptions) (*transactions.Transaction, error) {
+	c.request("NewSignedTx")
+	return transactions.CreateTransaction(data, "test-chain", 1)
+}
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestBulkPermissions demonstrates granting and revoking read access on many streams at once.
+func TestBulkPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	reader := util.Unsafe_NewEthereumAddressFromString("0x3333333333333333333333333333333333333333")
+
+	var locators []types.StreamLocator
+	for _, name := range []string{"test-bulk-permissions-a", "test-bulk-permissions-b", "test-bulk-permissions-c"} {
+		streamId := util.GenerateStreamId(name)
+		t.Cleanup(func() {
+			destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+			assertNoErrorOrFail(t, err, "Failed to destroy stream")
+			waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+		})
+		deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+			{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+		})
+		locators = append(locators, tnClient.OwnStreamLocator(streamId))
+	}
+
+	// the first stream is already granted, so it should be skipped
+	stream, err := tnClient.LoadPrimitiveStream(locators[0])
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	txHash, err := stream.AllowReadWallet(ctx, reader)
+	assertNoErrorOrFail(t, err, "Failed to allow read wallet")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	params := types.BulkPermissionParams{
+		Permission:  types.ReadPermission,
+		Wallet:      reader,
+		Streams:     types.StreamSelector{Streams: locators},
+		Concurrency: 2,
+	}
+
+	results, err := tnClient.BulkGrantPermission(ctx, params)
+	assertNoErrorOrFail(t, err, "Failed to grant permissions")
+	assert.Empty(t, results.Failed())
+	if assert.Equal(t, 3, len(results)) {
+		assert.True(t, results[0].Skipped)
+		assert.False(t, results[1].Skipped)
+		assert.False(t, results[2].Skipped)
+	}
+
+	for _, locator := range locators {
+		stream, err := tnClient.LoadPrimitiveStream(locator)
+		assertNoErrorOrFail(t, err, "Failed to load stream")
+		wallets, err := stream.GetAllowedReadWallets(ctx)
+		assertNoErrorOrFail(t, err, "Failed to get allowed read wallets")
+		assert.Equal(t, 1, len(wallets))
+	}
+
+	// granted twice, so revoking must disable both rows
+	txHash, err = stream.AllowReadWallet(ctx, reader)
+	assertNoErrorOrFail(t, err, "Failed to allow read wallet")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	results, err = tnClient.BulkRevokePermission(ctx, params)
+	assertNoErrorOrFail(t, err, "Failed to revoke permissions")
+	assert.Empty(t, results.Failed())
+
+	for _, locator := range locators {
+		stream, err := tnClient.LoadPrimitiveStream(locator)
+		assertNoErrorOrFail(t, err, "Failed to load stream")
+		wallets, err := stream.GetAllowedReadWallets(ctx)
+		assertNoErrorOrFail(t, err, "Failed to get allowed read wallets")
+		assert.Equal(t, 0, len(wallets))
+	}
+
+	// streams allowed to compose that don't exist anymore don't get in the way of new grants
+	missingStream := tnClient.OwnStreamLocator(util.GenerateStreamId("test-bulk-permissions-missing"))
+	txHash, err = stream.AllowComposeStream(ctx, missingStream)
+	assertNoErrorOrFail(t, err, "Failed to allow compose stream")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	results, err = tnClient.BulkGrantPermission(ctx, types.BulkPermissionParams{
+		Permission:      types.ComposePermission,
+		ComposingStream: locators[1],
+		Streams:         types.StreamSelector{Streams: locators[:1]},
+	})
+	assertNoErrorOrFail(t, err, "Failed to grant permissions")
+	assert.Empty(t, results.Failed())
+	canCompose, err := stream.CanCompose(ctx, locators[1])
+	assertNoErrorOrFail(t, err, "Failed to check compose permission")
+	assert.True(t, canCompose)
+}
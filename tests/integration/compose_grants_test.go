@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestComposeGrants demonstrates deploying a composed stream over a child with private compose visibility.
+func TestComposeGrants(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	privateChildId := util.GenerateStreamId("test-compose-grants-private-child")
+	publicChildId := util.GenerateStreamId("test-compose-grants-public-child")
+	parentId := util.GenerateStreamId("test-compose-grants-parent")
+	for _, streamId := range []util.StreamId{parentId, privateChildId, publicChildId} {
+		streamId := streamId
+		t.Cleanup(func() {
+			destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+			assertNoErrorOrFail(t, err, "Failed to destroy stream")
+			waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+		})
+	}
+
+	for _, streamId := range []util.StreamId{privateChildId, publicChildId} {
+		deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+			{Value: 10, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+		})
+	}
+
+	privateChild, err := tnClient.LoadPrimitiveStream(tnClient.OwnStreamLocator(privateChildId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	txHash, err := privateChild.SetComposeVisibility(ctx, util.PrivateVisibility)
+	assertNoErrorOrFail(t, err, "Failed to set compose visibility")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	err = tnClient.DeployComposedStreamWithTaxonomy(ctx, parentId, types.Taxonomy{
+		TaxonomyItems: []types.TaxonomyItem{
+			{ChildStream: tnClient.OwnStreamLocator(privateChildId), Weight: 1},
+			{ChildStream: tnClient.OwnStreamLocator(publicChildId), Weight: 1},
+		},
+	}, types.WithComposeGrants())
+	assertNoErrorOrFail(t, err, "Failed to deploy composed stream")
+
+	parent := tnClient.OwnStreamLocator(parentId)
+	canCompose, err := privateChild.CanCompose(ctx, parent)
+	assertNoErrorOrFail(t, err, "Failed to check compose permission")
+	assert.True(t, canCompose)
+
+	composedStream, err := tnClient.LoadComposedStream(parent)
+	assertNoErrorOrFail(t, err, "Failed to load composed stream")
+	records, err := composedStream.GetRecord(ctx, types.GetRecordInput{})
+	assertNoErrorOrFail(t, err, "Failed to get records")
+	assert.Equal(t, 1, len(records))
+
+	// nothing left to grant
+	taxonomy, err := composedStream.DescribeTaxonomies(ctx, types.DescribeTaxonomiesParams{LatestVersion: true})
+	assertNoErrorOrFail(t, err, "Failed to describe taxonomies")
+	grantResults, err := composedStream.GrantComposeToPrivateChildren(ctx, taxonomy)
+	assertNoErrorOrFail(t, err, "Failed to grant compose")
+	assert.Equal(t, 2, len(grantResults))
+	assert.Empty(t, grantResults.TxHashes())
+	assert.Empty(t, grantResults.NotOwned())
+
+	// private children of other owners can't be granted, and are reported
+	otherPk, err := crypto.Secp256k1PrivateKeyFromHex("1111111111111111111111111111111111111111111111111111111111111111")
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	otherClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(&auth.EthPersonalSigner{Key: *otherPk}))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	otherChildId := util.GenerateStreamId("test-compose-grants-other-child")
+	t.Cleanup(func() {
+		destroyResult, err := otherClient.DestroyStream(ctx, otherChildId)
+		assertNoErrorOrFail(t, err, "Failed to destroy stream")
+		waitTxToBeMinedWithSuccess(t, ctx, otherClient, destroyResult)
+	})
+	deployTestPrimitiveStreamWithData(t, ctx, otherClient, otherChildId, []types.InsertRecordInput{
+		{Value: 10, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+	})
+	otherChild, err := otherClient.LoadPrimitiveStream(otherClient.OwnStreamLocator(otherChildId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	txHash, err = otherChild.SetComposeVisibility(ctx, util.PrivateVisibility)
+	assertNoErrorOrFail(t, err, "Failed to set compose visibility")
+	waitTxToBeMinedWithSuccess(t, ctx, otherClient, txHash)
+
+	grantResults, err = composedStream.GrantComposeToPrivateChildren(ctx, types.Taxonomy{
+		TaxonomyItems: []types.TaxonomyItem{{ChildStream: otherClient.OwnStreamLocator(otherChildId), Weight: 1}},
+	})
+	assertNoErrorOrFail(t, err, "Failed to grant compose")
+	assert.Empty(t, grantResults.TxHashes())
+	assert.Equal(t, []types.StreamLocator{otherClient.OwnStreamLocator(otherChildId)}, grantResults.NotOwned())
+}
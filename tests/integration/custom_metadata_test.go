@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"context"
+	"github.com/cockroachdb/apd/v3"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestCustomMetadata demonstrates storing and reading custom metadata keys.
+func TestCustomMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	streamId := util.GenerateStreamId("test-custom-metadata")
+	t.Cleanup(func() {
+		destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+		assertNoErrorOrFail(t, err, "Failed to destroy stream")
+		waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+	})
+
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+		{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+	})
+
+	stream, err := tnClient.LoadPrimitiveStream(tnClient.OwnStreamLocator(streamId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+
+	weight, _, err := apd.NewFromString("0.75")
+	assertNoErrorOrFail(t, err, "Failed to parse decimal")
+
+	txHash, err := stream.BatchInsertMetadata(ctx, []types.MetadataInput{
+		{Key: "revision", Value: types.NewMetadataValue(3)},
+		{Key: "audited", Value: types.NewMetadataValue(true)},
+		{Key: "confidence", Value: types.NewDecimalMetadataValue(*weight)},
+		{Key: "maintainer", Value: types.NewRefMetadataValue("0x5555555555555555555555555555555555555555")},
+	})
+	assertNoErrorOrFail(t, err, "Failed to insert metadata")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	records, err := stream.GetMetadata(ctx, types.GetMetadataParams{Key: "confidence", OnlyLatest: true})
+	assertNoErrorOrFail(t, err, "Failed to get metadata")
+	if assert.Equal(t, 1, len(records)) {
+		assert.Equal(t, types.MetadataTypeFloat, records[0].Type)
+		value, err := records[0].TypedValue()
+		assertNoErrorOrFail(t, err, "Failed to parse value")
+		d, err := value.AsDecimal()
+		assertNoErrorOrFail(t, err, "Failed to get decimal")
+		assert.Equal(t, 0, d.Cmp(weight))
+	}
+
+	records, err = stream.GetMetadata(ctx, types.GetMetadataParams{
+		Key: "maintainer",
+		Ref: "0x5555555555555555555555555555555555555555",
+	})
+	assertNoErrorOrFail(t, err, "Failed to get metadata by ref")
+	if assert.Equal(t, 1, len(records)) {
+		txHash, err = stream.DisableMetadata(ctx, records[0].RowId)
+		assertNoErrorOrFail(t, err, "Failed to disable metadata")
+		waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+	}
+
+	records, err = stream.GetMetadata(ctx, types.GetMetadataParams{Key: "maintainer"})
+	assertNoErrorOrFail(t, err, "Failed to get metadata")
+	assert.Empty(t, records)
+}
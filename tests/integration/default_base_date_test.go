@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestDefaultBaseDate demonstrates setting, reading and clearing the base date used by indexes.
+func TestDefaultBaseDate(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	streamId := util.GenerateStreamId("test-default-base-date")
+	t.Cleanup(func() {
+		destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+		assertNoErrorOrFail(t, err, "Failed to destroy stream")
+		waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+	})
+
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+		{Value: 100, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+		{Value: 200, DateValue: civil.Date{Year: 2020, Month: 1, Day: 2}},
+	})
+
+	stream, err := tnClient.LoadPrimitiveStream(tnClient.OwnStreamLocator(streamId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+
+	baseDate, err := stream.GetDefaultBaseDate(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get default base date")
+	assert.Nil(t, baseDate)
+
+	_, err = stream.SetDefaultBaseDate(ctx, civil.Date{Year: 2020, Month: 2, Day: 30})
+	assert.Error(t, err, "invalid dates should be rejected")
+
+	txHash, err := stream.SetDefaultBaseDate(ctx, civil.Date{Year: 2020, Month: 1, Day: 2})
+	assertNoErrorOrFail(t, err, "Failed to set default base date")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	baseDate, err = stream.GetDefaultBaseDate(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get default base date")
+	if assert.NotNil(t, baseDate) {
+		assert.Equal(t, "2020-01-02", baseDate.String())
+	}
+
+	index, err := stream.GetIndex(ctx, types.GetIndexInput{})
+	assertNoErrorOrFail(t, err, "Failed to get index")
+	if assert.Equal(t, 2, len(index)) {
+		assert.Equal(t, "50.000000000000000000", index[0].Value.String())
+	}
+
+	txHash, err = stream.ClearDefaultBaseDate(ctx)
+	assertNoErrorOrFail(t, err, "Failed to clear default base date")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	baseDate, err = stream.GetDefaultBaseDate(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get default base date")
+	assert.Nil(t, baseDate)
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestDiagnoseComposedRead demonstrates finding which children block a wallet from reading a composed stream.
+func TestDiagnoseComposedRead(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	reader := util.Unsafe_NewEthereumAddressFromString("0x4444444444444444444444444444444444444444")
+
+	childAId := util.GenerateStreamId("test-diagnose-read-child-a")
+	childBId := util.GenerateStreamId("test-diagnose-read-child-b")
+	parentId := util.GenerateStreamId("test-diagnose-read-parent")
+	for _, streamId := range []util.StreamId{parentId, childAId, childBId} {
+		streamId := streamId
+		t.Cleanup(func() {
+			destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+			assertNoErrorOrFail(t, err, "Failed to destroy stream")
+			waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+		})
+	}
+
+	for _, streamId := range []util.StreamId{childAId, childBId} {
+		deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+			{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+		})
+	}
+
+	childA := tnClient.OwnStreamLocator(childAId)
+	childB := tnClient.OwnStreamLocator(childBId)
+	parent := tnClient.OwnStreamLocator(parentId)
+
+	deployTestComposedStreamWithTaxonomy(t, ctx, tnClient, parentId, types.Taxonomy{
+		TaxonomyItems: []types.TaxonomyItem{
+			{ChildStream: childA, Weight: 1},
+			{ChildStream: childB, Weight: 1},
+		},
+	})
+
+	// the reader can read the parent, but not child A
+	childAStream, err := tnClient.LoadPrimitiveStream(childA)
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	txHash, err := childAStream.SetReadVisibility(ctx, util.PrivateVisibility)
+	assertNoErrorOrFail(t, err, "Failed to set read visibility")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	// the parent can't compose child B
+	childBStream, err := tnClient.LoadPrimitiveStream(childB)
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	txHash, err = childBStream.SetComposeVisibility(ctx, util.PrivateVisibility)
+	assertNoErrorOrFail(t, err, "Failed to set compose visibility")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	diagnosis, err := tnClient.DiagnoseComposedRead(ctx, parent, reader)
+	assertNoErrorOrFail(t, err, "Failed to diagnose read")
+	assert.False(t, diagnosis.IsReadable())
+	if assert.Equal(t, 2, len(diagnosis.Blockers), diagnosis.String()) {
+		assert.True(t, diagnosis.Blockers[0].Stream.Equals(childA))
+		assert.Equal(t, types.ReadPermission, diagnosis.Blockers[0].MissingPermission)
+		assert.True(t, diagnosis.Blockers[1].Stream.Equals(childB))
+		assert.Equal(t, types.ComposePermission, diagnosis.Blockers[1].MissingPermission)
+		if assert.NotNil(t, diagnosis.Blockers[1].Parent) {
+			assert.True(t, diagnosis.Blockers[1].Parent.Equals(parent))
+		}
+	}
+
+	// the owner is always allowed to read, but composing is still blocked
+	diagnosis, err = tnClient.DiagnoseComposedRead(ctx, parent, tnClient.Address())
+	assertNoErrorOrFail(t, err, "Failed to diagnose read")
+	assert.Equal(t, 1, len(diagnosis.Blockers), diagnosis.String())
+}
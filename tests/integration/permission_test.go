@@ -110,18 +110,37 @@ func TestPermissions(t *testing.T) {
 		_, err = readerPrimitiveStream.GetRecord(ctx, readInput)
 		assert.Error(t, err)
 
+		canRead, err := ownerPrimitiveStream.CanRead(ctx, readerAddress)
+		assertNoErrorOrFail(t, err, "Failed to check read access")
+		assert.False(t, canRead)
+
 		// ok - private with access
 		// allow read access to the reader
 		txHash, err = ownerPrimitiveStream.AllowReadWallet(ctx, readerAddress)
 		assertNoErrorOrFail(t, err, "Failed to allow read wallet")
 		waitTxToBeMinedWithSuccess(t, ctx, ownerTnClient, txHash)
 
+		canRead, err = ownerPrimitiveStream.CanRead(ctx, readerAddress)
+		assertNoErrorOrFail(t, err, "Failed to check read access")
+		assert.True(t, canRead)
+
 		// read the stream
 		rec, err = readerPrimitiveStream.GetRecord(ctx, readInput)
 		assertNoErrorOrFail(t, err, "Failed to read records")
 		checkRecords(t, rec)
 	})
 
+	// Test primitive stream wallet write permissions
+	t.Run("TestPrimitiveStreamWalletWritePermission", func(t *testing.T) {
+		canWrite, err := ownerPrimitiveStream.CanWrite(ctx, ownerTnClient.Address())
+		assertNoErrorOrFail(t, err, "Failed to check write access")
+		assert.True(t, canWrite, "owner should be able to write")
+
+		canWrite, err = ownerPrimitiveStream.CanWrite(ctx, readerAddress)
+		assertNoErrorOrFail(t, err, "Failed to check write access")
+		assert.False(t, canWrite)
+	})
+
 	// Test composed stream functionality and permissions
 	t.Run("TestComposedStream", func(t *testing.T) {
 		// Set up cleanup to destroy the composed stream after test completion
@@ -255,12 +274,20 @@ func TestPermissions(t *testing.T) {
 			_, err = readerComposedStream.GetRecord(ctx, readInput)
 			assert.Error(t, err)
 
+			canCompose, err := ownerPrimitiveStream.CanCompose(ctx, composedStreamLocator)
+			assertNoErrorOrFail(t, err, "Failed to check compose access")
+			assert.False(t, canCompose)
+
 			// ok - private with access
 			// allow compose access to the reader
 			txHash, err = ownerPrimitiveStream.AllowComposeStream(ctx, composedStreamLocator)
 			assertNoErrorOrFail(t, err, "Failed to allow compose stream")
 			waitTxToBeMinedWithSuccess(t, ctx, ownerTnClient, txHash)
 
+			canCompose, err = ownerPrimitiveStream.CanCompose(ctx, composedStreamLocator)
+			assertNoErrorOrFail(t, err, "Failed to check compose access")
+			assert.True(t, canCompose)
+
 			// read the stream
 			rec, err = readerComposedStream.GetRecord(ctx, readInput)
 			assertNoErrorOrFail(t, err, "Failed to read records")
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestStreamACL demonstrates declaring the desired permissions of a stream and reconciling them.
+func TestStreamACL(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	readerA := util.Unsafe_NewEthereumAddressFromString("0x1111111111111111111111111111111111111111")
+	readerB := util.Unsafe_NewEthereumAddressFromString("0x2222222222222222222222222222222222222222")
+
+	streamId := util.GenerateStreamId("test-stream-acl")
+	t.Cleanup(func() {
+		destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+		assertNoErrorOrFail(t, err, "Failed to destroy stream")
+		waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+	})
+
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+		{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+	})
+
+	stream, err := tnClient.LoadPrimitiveStream(tnClient.OwnStreamLocator(streamId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+
+	var reconcile = func(t *testing.T, desired types.StreamACL) types.ACLPlan {
+		plan, err := stream.PlanACL(ctx, desired)
+		assertNoErrorOrFail(t, err, "Failed to plan ACL")
+		txHashes, err := stream.ApplyACLPlan(ctx, plan)
+		assertNoErrorOrFail(t, err, "Failed to apply ACL plan")
+		assert.LessOrEqual(t, len(txHashes), 2)
+		for _, txHash := range txHashes {
+			waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+		}
+		return plan
+	}
+
+	private := util.PrivateVisibility
+
+	// private, readable by A and B
+	plan := reconcile(t, types.StreamACL{
+		ReadVisibility:     &private,
+		AllowedReadWallets: []util.EthereumAddress{readerA, readerB},
+	})
+	assert.Equal(t, 3, len(plan.Inserts))
+	assert.Equal(t, 0, len(plan.Disables))
+
+	// readable by B only
+	plan = reconcile(t, types.StreamACL{
+		ReadVisibility:     &private,
+		AllowedReadWallets: []util.EthereumAddress{readerB},
+	})
+	assert.Equal(t, 0, len(plan.Inserts))
+	assert.Equal(t, 1, len(plan.Disables))
+
+	wallets, err := stream.GetAllowedReadWallets(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get allowed read wallets")
+	if assert.Equal(t, 1, len(wallets)) {
+		assert.Equal(t, readerB.Address(), wallets[0].Address())
+	}
+
+	// nothing left to do
+	plan, err = stream.PlanACL(ctx, types.StreamACL{
+		ReadVisibility:     &private,
+		AllowedReadWallets: []util.EthereumAddress{readerB},
+	})
+	assertNoErrorOrFail(t, err, "Failed to plan ACL")
+	assert.True(t, plan.IsEmpty(), "Unexpected changes: %s", plan)
+}
@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestStreamCache checks that a client with a stream cache loads each stream once, and drops destroyed ones
+func TestStreamCache(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider,
+		tnclient.WithSigner(signer),
+		tnclient.WithStreamCache(),
+		tnclient.WithLazyLoading(),
+	)
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	streamId := util.GenerateStreamId("test-stream-cache")
+	locator := tnClient.OwnStreamLocator(streamId)
+
+	// lazy loads don't fail for missing streams, but their requests do
+	missing, err := tnClient.LoadStreamContext(ctx, locator)
+	assertNoErrorOrFail(t, err, "Lazy load should not fail")
+	_, err = missing.GetType(ctx)
+	assert.ErrorIs(t, err, contractsapi.ErrorStreamNotFound)
+
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+		{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+	})
+
+	// the handle of the missing stream checks again on its next request
+	streamType, err := missing.GetType(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get type")
+	assert.Equal(t, types.StreamTypePrimitive, streamType)
+
+	loaded, err := tnClient.LoadStreamContext(ctx, locator)
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+	assert.Same(t, missing, loaded)
+
+	destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+	assertNoErrorOrFail(t, err, "Failed to destroy stream")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+
+	reloaded, err := tnClient.LoadStreamContext(ctx, locator)
+	assertNoErrorOrFail(t, err, "Lazy load should not fail")
+	assert.NotSame(t, loaded, reloaded)
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"context"
+	"github.com/golang-sql/civil"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestStreamDescription demonstrates describing what a stream measures.
+func TestStreamDescription(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	streamId := util.GenerateStreamId("test-stream-description")
+	t.Cleanup(func() {
+		destroyResult, err := tnClient.DestroyStream(ctx, streamId)
+		assertNoErrorOrFail(t, err, "Failed to destroy stream")
+		waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+	})
+
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, streamId, []types.InsertRecordInput{
+		{Value: 1, DateValue: civil.Date{Year: 2020, Month: 1, Day: 1}},
+	})
+
+	stream, err := tnClient.LoadPrimitiveStream(tnClient.OwnStreamLocator(streamId))
+	assertNoErrorOrFail(t, err, "Failed to load stream")
+
+	description := types.StreamDescription{
+		DisplayName:        "Egg prices",
+		Description:        "Average retail price of a dozen eggs",
+		Unit:               "USD",
+		Frequency:          types.FrequencyMonthly,
+		SourceURL:          "https://example.com/eggs",
+		MethodologyVersion: "1.0",
+	}
+	txHash, err := stream.SetDescription(ctx, description)
+	assertNoErrorOrFail(t, err, "Failed to set description")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	// only the unit changes
+	txHash, err = stream.SetDescription(ctx, types.StreamDescription{Unit: "EUR"})
+	assertNoErrorOrFail(t, err, "Failed to set description")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	got, err := stream.GetDescription(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get description")
+	description.Unit = "EUR"
+	assert.Equal(t, description, got)
+
+	displayName, err := stream.GetDisplayName(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get display name")
+	assert.Equal(t, "Egg prices", displayName)
+
+	// both unit rows are disabled, so neither shows up again
+	txHash, err = stream.ClearDescriptionFields(ctx, types.UnitKey)
+	assertNoErrorOrFail(t, err, "Failed to clear unit")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, txHash)
+
+	got, err = stream.GetDescription(ctx)
+	assertNoErrorOrFail(t, err, "Failed to get description")
+	description.Unit = ""
+	assert.Equal(t, description, got)
+
+	_, err = stream.SetDescription(ctx, types.StreamDescription{})
+	assert.Error(t, err, "empty descriptions should be rejected")
+}
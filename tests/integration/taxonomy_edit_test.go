@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"context"
+	"github.com/kwilteam/kwil-db/core/crypto"
+	"github.com/kwilteam/kwil-db/core/crypto/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/trufnetwork/sdk-go/core/contractsapi"
+	"github.com/trufnetwork/sdk-go/core/tnclient"
+	"github.com/trufnetwork/sdk-go/core/types"
+	"github.com/trufnetwork/sdk-go/core/util"
+	"testing"
+)
+
+// TestTaxonomyEdits demonstrates adding, reweighting and removing children of a composed stream
+// without rebuilding the whole taxonomy by hand.
+func TestTaxonomyEdits(t *testing.T) {
+	ctx := context.Background()
+
+	pk, err := crypto.Secp256k1PrivateKeyFromHex(TestPrivateKey)
+	assertNoErrorOrFail(t, err, "Failed to parse private key")
+	signer := &auth.EthPersonalSigner{Key: *pk}
+	tnClient, err := tnclient.NewClient(ctx, TestKwilProvider, tnclient.WithSigner(signer))
+	assertNoErrorOrFail(t, err, "Failed to create client")
+
+	composedStreamId := util.GenerateStreamId("test-taxonomy-edits-composed")
+	childAStreamId := util.GenerateStreamId("test-taxonomy-edits-child-a")
+	childBStreamId := util.GenerateStreamId("test-taxonomy-edits-child-b")
+
+	childA := tnClient.OwnStreamLocator(childAStreamId)
+	childB := tnClient.OwnStreamLocator(childBStreamId)
+
+	t.Cleanup(func() {
+		for _, id := range []util.StreamId{composedStreamId, childAStreamId, childBStreamId} {
+			destroyResult, err := tnClient.DestroyStream(ctx, id)
+			assertNoErrorOrFail(t, err, "Failed to destroy stream")
+			waitTxToBeMinedWithSuccess(t, ctx, tnClient, destroyResult)
+		}
+	})
+
+	// every version has its own start date, so the contract always knows which weight is the latest
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, childAStreamId, []types.InsertRecordInput{
+		{Value: 1, DateValue: *unsafeParseDate("2020-01-01")},
+		{Value: 1, DateValue: *unsafeParseDate("2020-01-02")},
+	})
+	deployTestPrimitiveStreamWithData(t, ctx, tnClient, childBStreamId, []types.InsertRecordInput{
+		{Value: 3, DateValue: *unsafeParseDate("2020-01-01")},
+		{Value: 3, DateValue: *unsafeParseDate("2020-01-02")},
+	})
+	deployTestComposedStreamWithTaxonomy(t, ctx, tnClient, composedStreamId, types.Taxonomy{
+		TaxonomyItems: []types.TaxonomyItem{{ChildStream: childA, Weight: 1}},
+		StartDate:     unsafeParseDate("2020-01-01"),
+	})
+
+	composedStream, err := tnClient.LoadComposedStream(tnClient.OwnStreamLocator(composedStreamId))
+	assertNoErrorOrFail(t, err, "Failed to load composed stream")
+
+	getRecordAt := func(t *testing.T, date string) string {
+		records, err := composedStream.GetRecord(ctx, types.GetRecordInput{
+			DateFrom: unsafeParseDate(date),
+			DateTo:   unsafeParseDate(date),
+		})
+		assertNoErrorOrFail(t, err, "Failed to get records")
+		if !assert.Equal(t, 1, len(records)) {
+			return ""
+		}
+		return records[0].Value.String()
+	}
+
+	// add child B without a date, keeping the date of the dated version
+	result, err := composedStream.AddChild(ctx, types.AddChildParams{
+		Child: types.TaxonomyItem{ChildStream: childB, Weight: 1},
+	})
+	assertNoErrorOrFail(t, err, "Failed to add child")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, result.TxHash)
+	assert.Equal(t, 1, len(result.Diff.Added))
+	assert.Equal(t, 2, len(result.Next.TaxonomyItems))
+	assert.Equal(t, unsafeParseDate("2020-01-01"), result.Next.StartDate)
+
+	// (1 * 1 + 3 * 1) / (1 + 1) = 2
+	assert.Equal(t, "2.000000000000000000", getRecordAt(t, "2020-01-01"))
+
+	// adding it again is rejected
+	_, err = composedStream.AddChild(ctx, types.AddChildParams{
+		Child: types.TaxonomyItem{ChildStream: childB, Weight: 1},
+	})
+	assert.ErrorIs(t, err, contractsapi.ErrorTaxonomyChildExists)
+
+	// reweighting needs a date after the previous version
+	_, err = composedStream.UpdateWeights(ctx, types.UpdateWeightsParams{
+		Weights: []types.TaxonomyItem{{ChildStream: childB, Weight: 3}},
+	})
+	assert.ErrorIs(t, err, contractsapi.ErrorTaxonomyStartDate)
+	_, err = composedStream.UpdateWeights(ctx, types.UpdateWeightsParams{
+		Weights:   []types.TaxonomyItem{{ChildStream: childB, Weight: 3}},
+		StartDate: unsafeParseDate("2019-12-31"),
+	})
+	assert.ErrorIs(t, err, contractsapi.ErrorTaxonomyStartDate)
+
+	// reweight child B from the next day
+	result, err = composedStream.UpdateWeights(ctx, types.UpdateWeightsParams{
+		Weights:   []types.TaxonomyItem{{ChildStream: childB, Weight: 3}},
+		StartDate: unsafeParseDate("2020-01-02"),
+	})
+	assertNoErrorOrFail(t, err, "Failed to update weights")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, result.TxHash)
+	if assert.Equal(t, 1, len(result.Diff.Reweighted)) {
+		assert.Equal(t, 1.0, result.Diff.Reweighted[0].PreviousWeight)
+		assert.Equal(t, 3.0, result.Diff.Reweighted[0].NextWeight)
+	}
+
+	// the previous weights still apply before the new start date
+	assert.Equal(t, "2.000000000000000000", getRecordAt(t, "2020-01-01"))
+	// (1 * 1 + 3 * 3) / (1 + 3) = 2.5
+	assert.Equal(t, "2.500000000000000000", getRecordAt(t, "2020-01-02"))
+
+	// remove child A
+	result, err = composedStream.RemoveChild(ctx, types.RemoveChildParams{Child: childA})
+	assertNoErrorOrFail(t, err, "Failed to remove child")
+	waitTxToBeMinedWithSuccess(t, ctx, tnClient, result.TxHash)
+	assert.Equal(t, 1, len(result.Diff.Removed))
+
+	taxonomy, err := composedStream.DescribeTaxonomies(ctx, types.DescribeTaxonomiesParams{LatestVersion: true})
+	assertNoErrorOrFail(t, err, "Failed to describe taxonomies")
+	if assert.Equal(t, 1, len(taxonomy.TaxonomyItems)) {
+		assert.True(t, taxonomy.TaxonomyItems[0].ChildStream.Equals(childB))
+	}
+
+	// removing the last child is rejected
+	_, err = composedStream.RemoveChild(ctx, types.RemoveChildParams{Child: childB})
+	assert.ErrorIs(t, err, contractsapi.ErrorTaxonomyEmpty)
+}
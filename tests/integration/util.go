@@ -30,11 +30,8 @@ func unsafeParseDate(dateStr string) *civil.Date {
 
 // waitTxToBeMinedWithSuccess waits for a transaction to be successful, failing the test if it fails.
 func waitTxToBeMinedWithSuccess(t *testing.T, ctx context.Context, client *tnclient.Client, txHash transactions.TxHash) {
-	txRes, err := client.WaitForTx(ctx, txHash, time.Second)
+	_, err := client.WaitForTxSuccess(ctx, txHash, time.Second)
 	assertNoErrorOrFail(t, err, "Transaction failed")
-	if !assert.Equal(t, transactions.CodeOk, transactions.TxCode(txRes.TxResult.Code), "Transaction code not OK: %s", txRes.TxResult.Log) {
-		t.FailNow()
-	}
 }
 
 // assertNoErrorOrFail asserts that an error is nil, failing the test if it is not.